@@ -2,19 +2,12 @@ package a
 
 import (
 	"github.com/westcoastcode-se/gohtml/h"
-	"io"
 	"strconv"
 )
 
+// Attrib emits a single attribute
 func Attrib(key string, value string) h.Node {
-	return func(b byte, w io.Writer) byte {
-		_, _ = w.Write([]byte{' '})
-		_, _ = w.Write([]byte(key))
-		_, _ = w.Write([]byte("=\""))
-		_, _ = w.Write([]byte(value))
-		_, _ = w.Write([]byte{'"'})
-		return b
-	}
+	return h.Attrib(key, value)
 }
 
 func ID(id string) h.Node {
@@ -25,6 +18,8 @@ func Class(classes string) h.Node {
 	return Attrib("class", classes)
 }
 
+// ClassIf emits a class attribute if the test is true. Use Classes if you have more than one class that might be
+// added to the same element, since each call to ClassIf emits its own class attribute
 func ClassIf(classes string, optional bool) h.Node {
 	return h.NodeIf(optional, Class(classes))
 }
@@ -34,14 +29,6 @@ func ClassesIf(values ...h.Node) h.Node {
 	return h.Attribs("class", values...)
 }
 
-func Href(val string) h.Node {
-	return Attrib("href", val)
-}
-
-func Src(src string) h.Node {
-	return Attrib("src", src)
-}
-
 func Role(classes string) h.Node {
 	return Attrib("role", classes)
 }
@@ -99,10 +86,6 @@ func Method(val string) h.Node {
 	return Attrib("method", val)
 }
 
-func Action(val string) h.Node {
-	return Attrib("action", val)
-}
-
 func For(val string) h.Node {
 	return Attrib("for", val)
 }
@@ -118,3 +101,34 @@ func Rowspan(n int) h.Node {
 func Value(v string) h.Node {
 	return Attrib("value", v)
 }
+
+func Alt(val string) h.Node {
+	return Attrib("alt", val)
+}
+
+func Width(n int) h.Node {
+	return Attrib("width", strconv.Itoa(n))
+}
+
+func Height(n int) h.Node {
+	return Attrib("height", strconv.Itoa(n))
+}
+
+func SrcSet(val string) h.Node {
+	return Attrib("srcset", val)
+}
+
+func Sizes(val string) h.Node {
+	return Attrib("sizes", val)
+}
+
+const LoadingLazy = "lazy"
+const LoadingEager = "eager"
+
+func Loading(val string) h.Node {
+	return Attrib("loading", val)
+}
+
+func DateTime(val string) h.Node {
+	return Attrib("datetime", val)
+}
@@ -0,0 +1,105 @@
+package a
+
+import (
+	"github.com/westcoastcode-se/gohtml/h"
+	"sort"
+	"strings"
+)
+
+// ClassList is an ordered set of class names. All functions return a new list, which makes it safe for components to
+// add their own classes to a list supplied by the caller. Duplicate classes are removed and the classes are emitted
+// in the order they were first added
+//
+// Example:
+//
+//	h.Button(
+//	  a.Classes("btn").
+//	    If(primary, "btn-primary").
+//	    If(disabled, "disabled").
+//	    Merge(props.Class).
+//	    Node(),
+//	)
+type ClassList []string
+
+// Classes creates a new class list. Each argument can contain multiple classes separated by whitespace
+func Classes(classes ...string) ClassList {
+	return ClassList(nil).Add(classes...)
+}
+
+// Has returns true if the class is part of the list
+func (c ClassList) Has(class string) bool {
+	for _, cc := range c {
+		if cc == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns a new list with the supplied classes added. Each argument can contain multiple classes separated by whitespace
+func (c ClassList) Add(classes ...string) ClassList {
+	result := c[:len(c):len(c)]
+	for _, cc := range classes {
+		for _, class := range strings.Fields(cc) {
+			if !result.Has(class) {
+				result = append(result, class)
+			}
+		}
+	}
+	return result
+}
+
+// If returns a new list with the supplied classes added if the test is true
+func (c ClassList) If(test bool, classes ...string) ClassList {
+	if !test {
+		return c
+	}
+	return c.Add(classes...)
+}
+
+// IfMap returns a new list where every class in the map with the value true is added. The classes are added
+// in sorted order, since the iteration order of a map isn't stable
+func (c ClassList) IfMap(classes map[string]bool) ClassList {
+	keys := make([]string, 0, len(classes))
+	for k, v := range classes {
+		if v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return c.Add(keys...)
+}
+
+// Merge returns a new list containing the classes from all the supplied lists
+func (c ClassList) Merge(other ...ClassList) ClassList {
+	result := c
+	for _, o := range other {
+		result = result.Add(o...)
+	}
+	return result
+}
+
+// Remove returns a new list without the supplied classes
+func (c ClassList) Remove(classes ...string) ClassList {
+	remove := Classes(classes...)
+	var result ClassList
+	for _, cc := range c {
+		if !remove.Has(cc) {
+			result = append(result, cc)
+		}
+	}
+	return result
+}
+
+// String returns the classes separated by a space
+func (c ClassList) String() string {
+	return strings.Join(c, " ")
+}
+
+// Node emits a single class attribute containing all classes. Nothing is emitted if the list is empty
+func (c ClassList) Node() h.Node {
+	if len(c) == 0 {
+		return h.Empty()
+	}
+	return Class(c.String())
+}
@@ -0,0 +1,133 @@
+package a
+
+import (
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Attr is a single attribute
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// ValidName returns true if the supplied string can be used as an attribute name without any escaping. Letters,
+// digits and the characters -_:.@ are allowed, which covers attributes such as data-testid, hx-get and x-on:click
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == ':', c == '.', c == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SpreadAttrs emits the supplied attributes in the order they are supplied. Attributes with an invalid name are
+// ignored and all values are escaped
+//
+// Example:
+//
+//	func Button(text string, attrs ...a.Attr) h.Node {
+//	  return h.Button(
+//	    a.Class("btn"),
+//	    a.SpreadAttrs(attrs...),
+//	    h.Text(text),
+//	  )
+//	}
+func SpreadAttrs(attrs ...Attr) h.Node {
+	nodes := make([]h.Node, 0, len(attrs))
+	for _, attr := range attrs {
+		if !ValidName(attr.Key) {
+			continue
+		}
+		nodes = append(nodes, Attrib(attr.Key, html.EscapeString(attr.Value)))
+	}
+	return h.Join(nodes...)
+}
+
+// Spread emits all attributes in the supplied map. The attributes are sorted by name, since the iteration order of a map
+// isn't stable. Attributes with an invalid name are ignored and all values are escaped
+func Spread(attrs map[string]string) h.Node {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	list := make([]Attr, len(keys))
+	for i, k := range keys {
+		list[i] = Attr{Key: k, Value: attrs[k]}
+	}
+	return SpreadAttrs(list...)
+}
+
+// SpreadStruct emits the fields of a struct, or a pointer to a struct, that have an attr tag. The attributes are emitted
+// in the same order as the fields. Empty strings, false booleans and nil pointers are ignored. A true boolean is emitted
+// as an attribute with an empty value
+//
+// Example:
+//
+//	type ButtonAttrs struct {
+//	  TestID   string `attr:"data-testid"`
+//	  HxGet    string `attr:"hx-get"`
+//	  Disabled bool   `attr:"disabled"`
+//	}
+//
+//	h.Button(a.SpreadStruct(ButtonAttrs{TestID: "save"}))
+func SpreadStruct(v any) h.Node {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return h.Empty()
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return h.Empty()
+	}
+
+	var attrs []Attr
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		key, ok := f.Tag.Lookup("attr")
+		if !ok || key == "-" || !f.IsExported() {
+			continue
+		}
+		if value, ok := attrValue(rv.Field(i)); ok {
+			attrs = append(attrs, Attr{Key: key, Value: value})
+		}
+	}
+	return SpreadAttrs(attrs...)
+}
+
+// attrValue converts a field into an attribute value. Returns false if the attribute should be ignored
+func attrValue(v reflect.Value) (string, bool) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), v.Len() > 0
+	case reflect.Bool:
+		return "", v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
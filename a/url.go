@@ -0,0 +1,78 @@
+package a
+
+import (
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"strings"
+)
+
+// BlockedURL replaces URLs with a scheme that isn't safe, such as javascript:
+const BlockedURL = "about:invalid#blocked"
+
+// SafeSchemes are the schemes allowed by Href, Src and Action. Relative URLs are always allowed
+var SafeSchemes = []string{"http", "https", "mailto", "tel"}
+
+// schemeOf returns the lower-case scheme of the URL, or an empty string if the URL is relative. Whitespace and
+// control characters are ignored, since browsers ignore them as well
+func schemeOf(url string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, url)
+	i := strings.IndexAny(cleaned, ":/?#")
+	if i == -1 || cleaned[i] != ':' {
+		return ""
+	}
+	return strings.ToLower(cleaned[:i])
+}
+
+// IsSafeURL returns true if the URL is relative or if the scheme is one of the SafeSchemes
+func IsSafeURL(url string) bool {
+	scheme := schemeOf(url)
+	if scheme == "" {
+		return true
+	}
+	for _, s := range SafeSchemes {
+		if scheme == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeURL returns the URL if it's safe. Otherwise, BlockedURL is returned
+func SafeURL(url string) string {
+	if IsSafeURL(url) {
+		return url
+	}
+	return BlockedURL
+}
+
+// isImageData returns true if the URL is an inlined image, such as data:image/png;base64,...
+func isImageData(url string) bool {
+	return schemeOf(url) == "data" && strings.HasPrefix(strings.ToLower(strings.TrimSpace(url)), "data:image/")
+}
+
+// Href emits a href attribute. URLs with a scheme that isn't safe, such as javascript:, are replaced with
+// BlockedURL. The URL is escaped after it has been checked, which means that it must not be escaped already.
+// Use Attrib if the URL is trusted and must be emitted as it is
+func Href(val string) h.Node {
+	return Attrib("href", html.EscapeString(SafeURL(val)))
+}
+
+// Src emits a src attribute. URLs with a scheme that isn't safe are replaced with BlockedURL. Inlined images,
+// data:image/..., are allowed as well. The URL is escaped after it has been checked
+func Src(src string) h.Node {
+	if isImageData(src) {
+		return Attrib("src", html.EscapeString(src))
+	}
+	return Attrib("src", html.EscapeString(SafeURL(src)))
+}
+
+// Action emits an action attribute. URLs with a scheme that isn't safe are replaced with BlockedURL. The URL is
+// escaped after it has been checked
+func Action(val string) h.Node {
+	return Attrib("action", html.EscapeString(SafeURL(val)))
+}
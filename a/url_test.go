@@ -0,0 +1,35 @@
+package a
+
+import (
+	"github.com/westcoastcode-se/gohtml/h"
+	"strings"
+	"testing"
+)
+
+func TestURLAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     h.Node
+		expected string
+	}{
+		{"relative", Href("/x?a=1&b=2"), `<a href="/x?a=1&amp;b=2"></a>`},
+		{"https", Href("https://example.com"), `<a href="https://example.com"></a>`},
+		{"javascript", Href("javascript:alert(1)"), `<a href="about:invalid#blocked"></a>`},
+		{"javascript with whitespace", Href(" java\tscript:alert(1)"), `<a href="about:invalid#blocked"></a>`},
+		{"quote", Href(`/x" onmouseover="alert(1)`), `<a href="/x&#34; onmouseover=&#34;alert(1)"></a>`},
+		{"encoded colon", Href("javascript&colon;alert(1)"), `<a href="javascript&amp;colon;alert(1)"></a>`},
+		{"src with quote", Src(`/x.png" onerror="alert(1)`), `<a src="/x.png&#34; onerror=&#34;alert(1)"></a>`},
+		{"image data with quote", Src(`data:image/png;base64,x" onerror="alert(1)`),
+			`<a src="data:image/png;base64,x&#34; onerror=&#34;alert(1)"></a>`},
+		{"action with quote", Action(`/x"><script>`), `<a action="/x&#34;&gt;&lt;script&gt;"></a>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			h.A(test.node)(0, sb)
+			if sb.String() != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, sb.String())
+			}
+		})
+	}
+}
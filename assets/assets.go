@@ -0,0 +1,295 @@
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Encodings of precompressed variants in the order they are preferred. A variant is a file with the same name
+// as the asset and the extension of the encoding added to it, for example "app.css.br"
+var encodings = []struct {
+	name string
+	ext  string
+}{
+	{name: "br", ext: ".br"},
+	{name: "gzip", ext: ".gz"},
+}
+
+// Props configures how assets are served
+type Props struct {
+	// Prefix is the URL path where the assets are served. Default is "/static/"
+	Prefix string
+	// HashLength is the number of characters of the content hash that is put in the URL. Default is 8
+	HashLength int
+	// InlineThreshold is the largest size, in bytes, of a file that is inlined by Stylesheet and Script.
+	// Default is 4096. Use a negative value to never inline any files
+	InlineThreshold int
+}
+
+// Asset is a single file in the file system
+type Asset struct {
+	// Name of the file in the file system, for example "css/app.css"
+	Name string
+	// Hash of the content
+	Hash string
+	// Path is the fingerprinted name of the file, for example "css/app.3f2a1c8d.css"
+	Path string
+	// ContentType is based on the file extension
+	ContentType string
+	// Size of the file in bytes
+	Size int64
+	// variants contains the name of the file for each precompressed encoding that exists
+	variants map[string]string
+}
+
+// Assets serves the files in a file system, normally an embed.FS, using fingerprinted URLs. Since the URL of a file
+// changes when the content changes the browser is allowed to cache the file forever.
+//
+// Example:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	var files = assets.MustNew(must(fs.Sub(static, "static")), assets.Props{})
+//
+//	func Index() h.Node {
+//	  return h.Html(
+//	    h.Head(
+//	      h.Link(a.Rel(a.RelStylesheet), files.Href("app.css")),
+//	    ),
+//	  )
+//	}
+//
+//	func main() {
+//	  http.Handle("/static/", files)
+//	}
+type Assets struct {
+	fsys   fs.FS
+	props  Props
+	byName map[string]*Asset
+	byPath map[string]*Asset
+	// inlined contains the decision made by Stylesheet and Script for each asset
+	mutex   sync.Mutex
+	inlined map[string]inlined
+}
+
+// New creates a new Assets instance by computing the hash of all files in the supplied file system
+func New(fsys fs.FS, props Props) (*Assets, error) {
+	if props.Prefix == "" {
+		props.Prefix = "/static/"
+	}
+	if props.HashLength <= 0 {
+		props.HashLength = 8
+	}
+	if props.InlineThreshold == 0 {
+		props.InlineThreshold = 4096
+	}
+	s := &Assets{
+		fsys:    fsys,
+		props:   props,
+		byName:  make(map[string]*Asset),
+		byPath:  make(map[string]*Asset),
+		inlined: make(map[string]inlined),
+	}
+
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		files[name] = true
+	}
+	for _, name := range names {
+		if isVariant(name, files) {
+			continue
+		}
+		asset, err := s.newAsset(name, files)
+		if err != nil {
+			return nil, err
+		}
+		s.byName[asset.Name] = asset
+		s.byPath[asset.Path] = asset
+	}
+	return s, nil
+}
+
+// MustNew creates a new Assets instance and panics if the file system can't be read
+func MustNew(fsys fs.FS, props Props) *Assets {
+	s, err := New(fsys, props)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// isVariant returns true if the file is a precompressed variant of another file
+func isVariant(name string, files map[string]bool) bool {
+	for _, e := range encodings {
+		if strings.HasSuffix(name, e.ext) && files[strings.TrimSuffix(name, e.ext)] {
+			return true
+		}
+	}
+	return false
+}
+
+// newAsset computes the hash of a file and finds all precompressed variants of it
+func (s *Assets) newAsset(name string, files map[string]bool) (*Asset, error) {
+	content, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])[:min(s.props.HashLength, 2*sha256.Size)]
+
+	ext := path.Ext(name)
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+	asset := &Asset{
+		Name:        name,
+		Hash:        hash,
+		Path:        strings.TrimSuffix(name, ext) + "." + hash + ext,
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		variants:    make(map[string]string),
+	}
+	for _, e := range encodings {
+		if files[name+e.ext] {
+			asset.variants[e.name] = name + e.ext
+		}
+	}
+	return asset, nil
+}
+
+// Lookup returns the asset with the supplied name
+func (s *Assets) Lookup(name string) (*Asset, bool) {
+	asset, ok := s.byName[strings.TrimPrefix(name, "/")]
+	return asset, ok
+}
+
+// URL returns the fingerprinted URL of the asset with the supplied name, for example "/static/app.3f2a1c8d.css". The
+// URL is not fingerprinted if the asset doesn't exist
+func (s *Assets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if asset, ok := s.byName[name]; ok {
+		return s.props.Prefix + asset.Path
+	}
+	return s.props.Prefix + name
+}
+
+// Href emits a href attribute with the fingerprinted URL of the asset with the supplied name
+func (s *Assets) Href(name string) h.Node {
+	return a.Href(s.URL(name))
+}
+
+// Src emits a src attribute with the fingerprinted URL of the asset with the supplied name
+func (s *Assets) Src(name string) h.Node {
+	return a.Src(s.URL(name))
+}
+
+// acceptsEncoding returns true if the Accept-Encoding header allows the supplied encoding. An entry for the encoding
+// itself takes precedence over "*", wherever it is in the header
+func acceptsEncoding(header string, encoding string) bool {
+	wildcard := false
+	for _, v := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(v, ";")
+		name = strings.TrimSpace(name)
+		accepted := true
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(q), 64); err == nil && f == 0 {
+				accepted = false
+			}
+		}
+		switch {
+		case strings.EqualFold(name, encoding):
+			return accepted
+		case name == "*":
+			wildcard = accepted
+		}
+	}
+	return wildcard
+}
+
+// ServeHTTP serves the assets. Fingerprinted URLs are cached forever by the browser. The original name of an
+// asset can also be used, but the browser has to check if the content has changed every time it's used.
+// A precompressed variant is served if it exists and the browser accepts the encoding
+func (s *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	name, ok := strings.CutPrefix(r.URL.Path, s.props.Prefix)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := w.Header()
+	asset, ok := s.byPath[name]
+	if ok {
+		header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else if asset, ok = s.byName[name]; ok {
+		header.Set("Cache-Control", "no-cache")
+	} else {
+		http.NotFound(w, r)
+		return
+	}
+
+	file := asset.Name
+	etag := asset.Hash
+	if len(asset.variants) > 0 {
+		header.Add("Vary", "Accept-Encoding")
+		for _, e := range encodings {
+			if v, ok := asset.variants[e.name]; ok && acceptsEncoding(r.Header.Get("Accept-Encoding"), e.name) {
+				file = v
+				etag = asset.Hash + "-" + e.name
+				header.Set("Content-Encoding", e.name)
+				break
+			}
+		}
+	}
+	header.Set("Content-Type", asset.ContentType)
+	header.Set("ETag", `"`+etag+`"`)
+
+	f, err := s.fsys.Open(file)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(b)
+	}
+	http.ServeContent(w, r, "", time.Time{}, content)
+}
@@ -0,0 +1,32 @@
+package assets
+
+import "testing"
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"", false},
+		{"br", true},
+		{"gzip, deflate, br", true},
+		{"BR", true},
+		{"gzip", false},
+		{"br;q=0", false},
+		{"br; q=0.0", false},
+		{"br;q=0.5", true},
+		{"*", true},
+		{"*;q=0", false},
+		{"*, br;q=0", false},
+		{"br;q=0, *", false},
+		{"*;q=0, br", true},
+		{"gzip, *;q=0", false},
+	}
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			if actual := acceptsEncoding(test.header, "br"); actual != test.expected {
+				t.Errorf("expected %v but was %v", test.expected, actual)
+			}
+		})
+	}
+}
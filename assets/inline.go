@@ -0,0 +1,118 @@
+package assets
+
+import (
+	"bytes"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"io/fs"
+)
+
+// inlined is the decision made for a single asset. The content is nil if the asset is linked
+type inlined struct {
+	content []byte
+}
+
+// escapeEndTag makes sure that the content of a style tag doesn't end the tag prematurely, by replacing the "/" in
+// every "</tag" with "\/". The result means the same thing in CSS, both inside and outside strings. The comparison
+// is case-insensitive, just like in the browser
+func escapeEndTag(content []byte, tag string) []byte {
+	needle := []byte("</" + tag)
+	lower := bytes.ToLower(content)
+	if !bytes.Contains(lower, needle) {
+		return content
+	}
+	result := make([]byte, 0, len(content)+8)
+	for {
+		i := bytes.Index(lower, needle)
+		if i == -1 {
+			return append(result, content...)
+		}
+		result = append(result, content[:i+1]...)
+		result = append(result, '\\')
+		content = content[i+1:]
+		lower = lower[i+1:]
+	}
+}
+
+// unsafeScript contains the character sequences that change how the browser parses the content of a script tag
+var unsafeScript = [][]byte{[]byte("</script"), []byte("<script"), []byte("<!--")}
+
+// canInlineScript returns false if the javascript contains something that might end the script tag, or make the
+// browser treat the rest of the document as part of the script. Escaping them would require knowing if they are
+// inside a string or not, so such scripts are linked instead
+func canInlineScript(content []byte) bool {
+	lower := bytes.ToLower(content)
+	for _, s := range unsafeScript {
+		if bytes.Contains(lower, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// inline returns the content of the asset if it's small enough to be inlined. The decision is made once per asset
+func (s *Assets) inline(name string, tag string) ([]byte, bool, error) {
+	asset, ok := s.Lookup(name)
+	if !ok {
+		return nil, false, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	key := tag + ":" + asset.Name
+	s.mutex.Lock()
+	decision, ok := s.inlined[key]
+	s.mutex.Unlock()
+	if ok {
+		return decision.content, decision.content != nil, nil
+	}
+
+	if s.props.InlineThreshold >= 0 && asset.Size <= int64(s.props.InlineThreshold) {
+		content, err := fs.ReadFile(s.fsys, asset.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		switch {
+		case tag == "style":
+			decision.content = escapeEndTag(content, tag)
+		case canInlineScript(content):
+			decision.content = content
+		}
+	}
+	s.mutex.Lock()
+	s.inlined[key] = decision
+	s.mutex.Unlock()
+	return decision.content, decision.content != nil, nil
+}
+
+// Stylesheet emits the content of the asset in a style tag if the asset is smaller than the inline threshold.
+// Otherwise, a link to the fingerprinted URL of the asset is emitted. The rendering fails if the asset doesn't exist
+//
+// Example:
+//
+//	h.Head(
+//	  files.Stylesheet("critical.css"),
+//	)
+func (s *Assets) Stylesheet(name string, c ...h.Node) h.Node {
+	content, ok, err := s.inline(name, "style")
+	if err != nil {
+		return h.Fail(err)
+	}
+	if ok {
+		return h.Style(append(c[:len(c):len(c)], h.Bytes(content))...)
+	}
+	return h.Link(append([]h.Node{a.Rel(a.RelStylesheet), s.Href(name)}, c...)...)
+}
+
+// Script emits the content of the asset in a script tag if the asset is smaller than the inline threshold.
+// Otherwise, a script tag with the fingerprinted URL of the asset is emitted. Scripts containing "</script",
+// "<script" or "<!--" are never inlined, since they can't be escaped without changing what they mean. The rendering
+// fails if the asset doesn't exist
+func (s *Assets) Script(name string, c ...h.Node) h.Node {
+	content, ok, err := s.inline(name, "script")
+	if err != nil {
+		return h.Fail(err)
+	}
+	if ok {
+		return h.Script(append(c[:len(c):len(c)], h.Bytes(content))...)
+	}
+	return h.Script(append([]h.Node{s.Src(name)}, c...)...)
+}
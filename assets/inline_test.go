@@ -0,0 +1,47 @@
+package assets
+
+import (
+	"github.com/westcoastcode-se/gohtml/h"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestInline(t *testing.T) {
+	files, err := New(fstest.MapFS{
+		"app.js":      {Data: []byte("a();")},
+		"end.js":      {Data: []byte(`document.write("</SCRIPT>");`)},
+		"start.js":    {Data: []byte(`var s = "<script>";`)},
+		"comment.js":  {Data: []byte(`var s = "<!--";`)},
+		"compare.js":  {Data: []byte(`if (a</script/.test(s)) {}`)},
+		"app.css":     {Data: []byte("p{}")},
+		"end.css":     {Data: []byte(`p::after{content:"</style>"}`)},
+		"comment.css": {Data: []byte(`p::after{content:"<!--"}`)},
+	}, Props{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		node     h.Node
+		expected string
+	}{
+		{"script", files.Script("app.js"), `<script>a();</script>`},
+		{"end tag in script", files.Script("end.js"), `<script src="` + files.URL("end.js") + `"></script>`},
+		{"start tag in script", files.Script("start.js"), `<script src="` + files.URL("start.js") + `"></script>`},
+		{"comment in script", files.Script("comment.js"), `<script src="` + files.URL("comment.js") + `"></script>`},
+		{"comparison in script", files.Script("compare.js"), `<script src="` + files.URL("compare.js") + `"></script>`},
+		{"style", files.Stylesheet("app.css"), `<style>p{}</style>`},
+		{"end tag in style", files.Stylesheet("end.css"), `<style>p::after{content:"<\/style>"}</style>`},
+		{"comment in style", files.Stylesheet("comment.css"), `<style>p::after{content:"<!--"}</style>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			test.node(0, sb)
+			if sb.String() != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, sb.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package csp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Source expressions with a special meaning
+const (
+	Self          = "'self'"
+	None          = "'none'"
+	StrictDynamic = "'strict-dynamic'"
+	UnsafeEval    = "'unsafe-eval'"
+	UnsafeHashes  = "'unsafe-hashes'"
+	Data          = "data:"
+	HTTPS         = "https:"
+)
+
+// Directive is a single directive in a policy, such as script-src
+type Directive struct {
+	Name    string
+	Sources []string
+}
+
+// DefaultDirectives is used if no directives are configured. Nonces, or hashes, are added to script-src and style-src.
+//
+// Nonces and hashes only allow script and style tags. Style attributes, such as the ones emitted by css.Inline, by the
+// alignment of markdown table cells and by sanitize policies that allow styles, are blocked by this style-src. Allow
+// them by adding UnsafeHashes together with the Hash of each attribute value to style-src:
+//
+//	csp.Directive{Name: "style-src", Sources: []string{csp.Self, csp.UnsafeHashes, csp.Hash("text-align: center")}}
+var DefaultDirectives = []Directive{
+	{Name: "default-src", Sources: []string{Self}},
+	{Name: "script-src", Sources: []string{Self}},
+	{Name: "style-src", Sources: []string{Self}},
+	{Name: "img-src", Sources: []string{Self, Data}},
+	{Name: "object-src", Sources: []string{None}},
+	{Name: "base-uri", Sources: []string{Self}},
+	{Name: "frame-ancestors", Sources: []string{Self}},
+}
+
+// Props configures the content security policy
+type Props struct {
+	// Directives of the policy. DefaultDirectives is used if empty
+	Directives []Directive
+	// ReportOnly sends the policy using the Content-Security-Policy-Report-Only header
+	ReportOnly bool
+	// UseHashes adds the hash of each inline script and style to the policy instead of a nonce. Only tags rendered
+	// with the request context, such as h.Script and h.Style, are hashed. Tags written as raw text are not allowed
+	// by the policy. The whole response is buffered, since the policy header can't be written until all content has
+	// been rendered. Streaming responses, such as server-sent events, can't be used together with this option
+	UseHashes bool
+}
+
+// nonceKey is the context key for the nonce of the current request
+type nonceKey struct{}
+
+// Nonce returns the nonce of the current request. Returns an empty string if the request isn't
+// protected by the Middleware or if hashes are used
+func Nonce(ctx context.Context) string {
+	n, _ := ctx.Value(nonceKey{}).(string)
+	return n
+}
+
+// Hash returns the source expression for the sha256 hash of the supplied inline content
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// policy creates the header value where the supplied sources are added to script-src and style-src
+func policy(directives []Directive, scripts []string, styles []string) string {
+	sb := strings.Builder{}
+	found := map[string]bool{}
+	write := func(name string, sources []string) {
+		if sb.Len() > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(name)
+		for _, s := range sources {
+			sb.WriteByte(' ')
+			sb.WriteString(s)
+		}
+	}
+	for _, d := range directives {
+		sources := d.Sources
+		switch d.Name {
+		case "script-src":
+			sources = append(sources[:len(sources):len(sources)], scripts...)
+		case "style-src":
+			sources = append(sources[:len(sources):len(sources)], styles...)
+		}
+		found[d.Name] = true
+		write(d.Name, sources)
+	}
+	if !found["script-src"] && len(scripts) > 0 {
+		write("script-src", append([]string{Self}, scripts...))
+	}
+	if !found["style-src"] && len(styles) > 0 {
+		write("style-src", append([]string{Self}, styles...))
+	}
+	return sb.String()
+}
+
+// Middleware sets the Content-Security-Policy header on all responses. By default a random nonce is generated for
+// each request. The nonce is added to the policy and to all script and style tags rendered with the request
+// context, which means that the response must be rendered using h.WithContext(r.Context(), w)
+//
+// Example:
+//
+//	secure := csp.Middleware(csp.Props{})
+//	http.ListenAndServe(":8080", secure(mux))
+func Middleware(props Props) func(http.Handler) http.Handler {
+	directives := props.Directives
+	if len(directives) == 0 {
+		directives = DefaultDirectives
+	}
+	header := "Content-Security-Policy"
+	if props.ReportOnly {
+		header = "Content-Security-Policy-Report-Only"
+	}
+
+	return func(next http.Handler) http.Handler {
+		if props.UseHashes {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hc := &hashCollector{}
+				ctx := h.WithTagHook(r.Context(), hc.hook, "script", "style")
+				bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+				next.ServeHTTP(bw, r.WithContext(ctx))
+				w.Header().Set(header, policy(directives, hc.scripts, hc.styles))
+				w.WriteHeader(bw.status)
+				_, _ = w.Write(bw.buf.Bytes())
+			})
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b := make([]byte, 16)
+			_, _ = rand.Read(b)
+			nonce := base64.StdEncoding.EncodeToString(b)
+			source := "'nonce-" + nonce + "'"
+			w.Header().Set(header, policy(directives, []string{source}, []string{source}))
+
+			ctx := context.WithValue(r.Context(), nonceKey{}, nonce)
+			ctx = h.WithTagHook(ctx, func(t *h.OpenTag) h.Node {
+				t.SetAttr("nonce", nonce)
+				return nil
+			}, "script", "style")
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bufferedWriter keeps the whole response in memory so that the headers can be changed after the
+// content has been rendered
+type bufferedWriter struct {
+	http.ResponseWriter
+	buf    bytes.Buffer
+	status int
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+// hashCollector collects the hashes of all inline scripts and styles rendered during a request
+type hashCollector struct {
+	mutex   sync.Mutex
+	scripts []string
+	styles  []string
+}
+
+// hook computes the hash of the content of the tag when it's closed
+func (hc *hashCollector) hook(t *h.OpenTag) h.Node {
+	if _, external := t.Attr("src"); external {
+		return nil
+	}
+	name := t.Name
+	t.OnContent(func(content []byte) {
+		hash := Hash(string(content))
+		hc.mutex.Lock()
+		defer hc.mutex.Unlock()
+		hashes := &hc.styles
+		if name == "script" {
+			hashes = &hc.scripts
+		}
+		if !slices.Contains(*hashes, hash) {
+			*hashes = append(*hashes, hash)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,68 @@
+package csp
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func render(props Props, node h.Node) *httptest.ResponseRecorder {
+	handler := Middleware(props)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = h.Html(h.Body(node))(h.WithContext(r.Context(), w))
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	return rec
+}
+
+func TestHashes(t *testing.T) {
+	rec := render(Props{UseHashes: true}, h.Join(
+		h.Script(h.Text("a()")),
+		h.Script(a.Src("/x.js")),
+		h.Style(h.Text("p{}")),
+		h.Script(h.Text("a()")),
+	))
+	header := rec.Header().Get("Content-Security-Policy")
+	if !strings.Contains(header, "script-src 'self' "+Hash("a()")+";") {
+		t.Errorf("expected the hash of the script in %q", header)
+	}
+	if !strings.Contains(header, "style-src 'self' "+Hash("p{}")+";") {
+		t.Errorf("expected the hash of the style in %q", header)
+	}
+	if strings.Contains(header, Hash("")) {
+		t.Errorf("external scripts must not be hashed in %q", header)
+	}
+}
+
+func TestHashesIgnoreInjectedScripts(t *testing.T) {
+	// content written without escaping must never be allowed by the policy
+	rec := render(Props{UseHashes: true}, h.Join(
+		h.Text("<script>alert(1)</script>"),
+		h.Div(h.Attrib("title", `"><style>p{}</style>`)),
+	))
+	header := rec.Header().Get("Content-Security-Policy")
+	if strings.Contains(header, Hash("alert(1)")) || strings.Contains(header, Hash("p{}")) {
+		t.Errorf("injected content was hashed in %q", header)
+	}
+	if !strings.Contains(rec.Body.String(), "<script>alert(1)</script>") {
+		t.Errorf("expected the buffered response to be written but was %q", rec.Body.String())
+	}
+}
+
+func TestNonce(t *testing.T) {
+	rec := render(Props{}, h.Script(h.Text("a()")))
+	header := rec.Header().Get("Content-Security-Policy")
+	start := strings.Index(header, "'nonce-")
+	if start == -1 {
+		t.Fatalf("expected a nonce in %q", header)
+	}
+	nonce := header[start+len("'nonce-"):]
+	nonce = nonce[:strings.IndexByte(nonce, '\'')]
+	if expected := `<script nonce="` + nonce + `">a()</script>`; !strings.Contains(rec.Body.String(), expected) {
+		t.Errorf("expected %q in %q", expected, rec.Body.String())
+	}
+}
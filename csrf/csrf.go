@@ -0,0 +1,178 @@
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+)
+
+// tokenLength is the number of random bytes in a token
+const tokenLength = 32
+
+// tokenKey is the context key for the token of the current request
+type tokenKey struct{}
+
+// Props configures the CSRF protection
+type Props struct {
+	// CookieName is the name of the cookie containing the token. Defaults to "csrf_token"
+	CookieName string
+	// FieldName is the name of the hidden form field containing the token. Defaults to "csrf_token"
+	FieldName string
+	// HeaderName is the name of the header that can be used instead of the form field. Defaults to "X-CSRF-Token"
+	HeaderName string
+	// Path of the cookie. Defaults to "/"
+	Path string
+	// Secure forces the cookie to only be sent over https. The cookie is always secure if the request is made over https
+	Secure bool
+	// SameSite attribute of the cookie. Defaults to http.SameSiteLaxMode
+	SameSite http.SameSite
+	// InjectForms adds the hidden field to all forms with method post that are rendered with the request context
+	InjectForms bool
+	// FailureHandler is called when the verification fails. Responds with 403 Forbidden if nil
+	FailureHandler http.Handler
+}
+
+func (p *Props) defaults() {
+	if p.CookieName == "" {
+		p.CookieName = "csrf_token"
+	}
+	if p.FieldName == "" {
+		p.FieldName = "csrf_token"
+	}
+	if p.HeaderName == "" {
+		p.HeaderName = "X-CSRF-Token"
+	}
+	if p.Path == "" {
+		p.Path = "/"
+	}
+	if p.SameSite == 0 {
+		p.SameSite = http.SameSiteLaxMode
+	}
+	if p.FailureHandler == nil {
+		p.FailureHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+		})
+	}
+}
+
+// token is the secret stored in the cookie together with the names used when rendering the hidden field
+type token struct {
+	secret    []byte
+	fieldName string
+}
+
+// Middleware protects all requests, except GET, HEAD, OPTIONS and TRACE, from cross-site request forgery using the
+// double-submit cookie pattern. A random token is stored in a cookie and each form is expected to submit the same
+// token, either in a hidden field or in a header. The token is made available to the nodes through the request
+// context, which means that the response must be rendered using h.WithContext(r.Context(), w)
+//
+// Example:
+//
+//	protect := csrf.Middleware(csrf.Props{InjectForms: true})
+//	http.ListenAndServe(":8080", protect(mux))
+func Middleware(props Props) func(http.Handler) http.Handler {
+	props.defaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Cookie")
+			var secret []byte
+			if c, err := r.Cookie(props.CookieName); err == nil {
+				if b, err := base64.RawURLEncoding.DecodeString(c.Value); err == nil && len(b) == tokenLength {
+					secret = b
+				}
+			}
+
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			default:
+				submitted := r.Header.Get(props.HeaderName)
+				if submitted == "" {
+					submitted = r.PostFormValue(props.FieldName)
+				}
+				if secret == nil || !verify(secret, submitted) {
+					props.FailureHandler.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			if secret == nil {
+				secret = make([]byte, tokenLength)
+				_, _ = rand.Read(secret)
+				http.SetCookie(w, &http.Cookie{
+					Name:     props.CookieName,
+					Value:    base64.RawURLEncoding.EncodeToString(secret),
+					Path:     props.Path,
+					Secure:   props.Secure || r.TLS != nil,
+					HttpOnly: true,
+					SameSite: props.SameSite,
+				})
+			}
+
+			ctx := context.WithValue(r.Context(), tokenKey{}, token{secret: secret, fieldName: props.FieldName})
+			if props.InjectForms {
+				ctx = h.WithTagHook(ctx, func(t *h.OpenTag) h.Node {
+					if !t.HasAttrValue("method", http.MethodPost) {
+						return nil
+					}
+					return Field()
+				}, "form")
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// mask creates a new token by masking the secret with a random one-time pad. This makes the token different in each
+// response, which protects the secret against attacks such as BREACH
+func mask(secret []byte) string {
+	b := make([]byte, 2*len(secret))
+	pad := b[:len(secret)]
+	_, _ = rand.Read(pad)
+	for i := range secret {
+		b[len(secret)+i] = pad[i] ^ secret[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// verify returns true if the submitted token was created from the supplied secret
+func verify(secret []byte, submitted string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(submitted)
+	if err != nil || len(b) != 2*tokenLength {
+		return false
+	}
+	unmasked := make([]byte, tokenLength)
+	for i := range unmasked {
+		unmasked[i] = b[i] ^ b[tokenLength+i]
+	}
+	return subtle.ConstantTimeCompare(unmasked, secret) == 1
+}
+
+// Token returns a new token for the supplied context. Returns an empty string if the context isn't protected
+// by the Middleware. Use this when a token must be sent in a header, for example with hx.Headers
+func Token(ctx context.Context) string {
+	t, ok := ctx.Value(tokenKey{}).(token)
+	if !ok {
+		return ""
+	}
+	return mask(t.secret)
+}
+
+// Field emits a hidden input field containing the token of the render context. Nothing is emitted if
+// the request isn't protected by the Middleware
+func Field() h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		t, ok := ctx.Value(tokenKey{}).(token)
+		if !ok {
+			return h.Empty()
+		}
+		return h.Input(
+			a.Type("hidden"),
+			a.Name(t.fieldName),
+			a.Value(mask(t.secret)),
+		)
+	})
+}
@@ -0,0 +1,120 @@
+package css
+
+// Property is the name of a CSS property. Use the constants to avoid typos. Custom properties, such as --primary-color,
+// can be created by converting a string into a Property
+type Property string
+
+const (
+	AlignContent            Property = "align-content"
+	AlignItems              Property = "align-items"
+	AlignSelf               Property = "align-self"
+	Animation               Property = "animation"
+	AspectRatio             Property = "aspect-ratio"
+	Background              Property = "background"
+	BackgroundColor         Property = "background-color"
+	BackgroundImage         Property = "background-image"
+	BackgroundPosition      Property = "background-position"
+	BackgroundRepeat        Property = "background-repeat"
+	BackgroundSize          Property = "background-size"
+	Border                  Property = "border"
+	BorderBottom            Property = "border-bottom"
+	BorderColor             Property = "border-color"
+	BorderLeft              Property = "border-left"
+	BorderRadius            Property = "border-radius"
+	BorderRight             Property = "border-right"
+	BorderStyle             Property = "border-style"
+	BorderTop               Property = "border-top"
+	BorderWidth             Property = "border-width"
+	BottomProperty          Property = "bottom"
+	BoxShadow               Property = "box-shadow"
+	BoxSizing               Property = "box-sizing"
+	Color                   Property = "color"
+	ColumnGap               Property = "column-gap"
+	ContentProperty         Property = "content"
+	Cursor                  Property = "cursor"
+	Display                 Property = "display"
+	FlexBasis               Property = "flex-basis"
+	FlexDirection           Property = "flex-direction"
+	FlexGrow                Property = "flex-grow"
+	FlexShrink              Property = "flex-shrink"
+	FlexWrap                Property = "flex-wrap"
+	Float                   Property = "float"
+	FontFamily              Property = "font-family"
+	FontSize                Property = "font-size"
+	FontStyle               Property = "font-style"
+	FontWeight              Property = "font-weight"
+	Gap                     Property = "gap"
+	GridArea                Property = "grid-area"
+	GridColumn              Property = "grid-column"
+	GridRow                 Property = "grid-row"
+	GridTemplateAreas       Property = "grid-template-areas"
+	GridTemplateColumns     Property = "grid-template-columns"
+	GridTemplateRows        Property = "grid-template-rows"
+	Height                  Property = "height"
+	JustifyContent          Property = "justify-content"
+	JustifyItems            Property = "justify-items"
+	JustifySelf             Property = "justify-self"
+	LeftProperty            Property = "left"
+	LetterSpacing           Property = "letter-spacing"
+	LineHeight              Property = "line-height"
+	ListStyle               Property = "list-style"
+	Margin                  Property = "margin"
+	MarginBottom            Property = "margin-bottom"
+	MarginLeft              Property = "margin-left"
+	MarginRight             Property = "margin-right"
+	MarginTop               Property = "margin-top"
+	MaxHeight               Property = "max-height"
+	MaxWidth                Property = "max-width"
+	MinHeight               Property = "min-height"
+	MinWidth                Property = "min-width"
+	ObjectFit               Property = "object-fit"
+	Opacity                 Property = "opacity"
+	Order                   Property = "order"
+	Outline                 Property = "outline"
+	Overflow                Property = "overflow"
+	OverflowX               Property = "overflow-x"
+	OverflowY               Property = "overflow-y"
+	Padding                 Property = "padding"
+	PaddingBottom           Property = "padding-bottom"
+	PaddingLeft             Property = "padding-left"
+	PaddingRight            Property = "padding-right"
+	PaddingTop              Property = "padding-top"
+	PointerEvents           Property = "pointer-events"
+	Position                Property = "position"
+	RightProperty           Property = "right"
+	RowGap                  Property = "row-gap"
+	TextAlign               Property = "text-align"
+	TextDecoration          Property = "text-decoration"
+	TextOverflow            Property = "text-overflow"
+	TextTransform           Property = "text-transform"
+	TopProperty             Property = "top"
+	Transform               Property = "transform"
+	Transition              Property = "transition"
+	TransitionDuration      Property = "transition-duration"
+	UserSelect              Property = "user-select"
+	VerticalAlign           Property = "vertical-align"
+	Visibility              Property = "visibility"
+	WhiteSpace              Property = "white-space"
+	Width                   Property = "width"
+	WordBreak               Property = "word-break"
+	ZIndex                  Property = "z-index"
+	WebkitFontSmoothing     Property = "-webkit-font-smoothing"
+	WebkitTapHighlightColor Property = "-webkit-tap-highlight-color"
+)
+
+// Set creates a declaration for this property. Multiple values are separated by a space
+//
+// Example:
+//
+//	css.Margin.Set(css.Px(4), css.Auto)
+func (p Property) Set(v ...Value) Declaration {
+	return Declaration{
+		property: p,
+		value:    join(v, " "),
+	}
+}
+
+// Valid returns true if the property name is a valid CSS identifier
+func (p Property) Valid() bool {
+	return isIdent(string(p))
+}
@@ -0,0 +1,245 @@
+package css
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidName is returned when the name of a sheet can't be used as the prefix of a class name
+	ErrInvalidName = errors.New("css: invalid sheet name")
+	// ErrBundled is returned when a sheet is added to a bundle while it's already part of another bundle
+	ErrBundled = errors.New("css: sheet is already part of a bundle")
+)
+
+// isIdentChar returns true if the character can be part of an identifier
+func isIdentChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c >= 0x80
+}
+
+// validName returns true if the name can be used as the start of a class name. An empty name is allowed
+func validName(name string) bool {
+	if name == "" {
+		return true
+	}
+	if '0' <= name[0] && name[0] <= '9' || name[0] == '-' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// renameClasses renames all class selectors in the supplied selector. Attribute selectors are left untouched
+func renameClasses(selector string, rename func(class string) string) string {
+	sb := strings.Builder{}
+	brackets := 0
+	for i := 0; i < len(selector); i++ {
+		c := selector[i]
+		switch {
+		case c == '[':
+			brackets++
+		case c == ']' && brackets > 0:
+			brackets--
+		case c == '.' && brackets == 0:
+			j := i + 1
+			for j < len(selector) && isIdentChar(selector[j]) {
+				j++
+			}
+			if j > i+1 {
+				sb.WriteByte('.')
+				sb.WriteString(rename(selector[i+1 : j]))
+				i = j - 1
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// Sheet is a stylesheet that belongs to a single component. All class names in the sheet are replaced with unique
+// names, based on the name and content of the sheet, which means that two components can use the same class names
+// without colliding with each other. The sheet itself is emitted once in the head of the document the first time
+// one of its classes is used.
+//
+// Example:
+//
+//	var buttonSheet = css.NewSheet("button",
+//	  css.Select(".primary", css.Color.Set(css.Hex("#fff"))),
+//	  css.Select(".primary:hover", css.Opacity.Set(css.Num(0.8))),
+//	)
+//
+//	func Button(text string) h.Node {
+//	  return h.Button(
+//	    buttonSheet.Class("primary"),
+//	    h.Text(text),
+//	  )
+//	}
+type Sheet struct {
+	name    string
+	hash    string
+	css     string
+	classes map[string]string
+	bundle  *Bundle
+}
+
+// NewSheet creates a new scoped stylesheet. The name is used as a prefix for all generated class names, which means
+// that it may only contain letters, digits, '-' and '_' and must not start with a digit or a '-'. Panics if the name
+// is invalid or if any of the rules has an unsafe selector, since sheets are created when the application starts
+func NewSheet(name string, rules ...Rule) *Sheet {
+	if !validName(name) {
+		panic(fmt.Errorf("%w: %q", ErrInvalidName, name))
+	}
+	if err := Validate(rules...); err != nil {
+		panic(err)
+	}
+	// the hash is based on the unscoped content so that it can be part of the generated names
+	sum := sha256.Sum256([]byte(name + "\n" + Render(rules...)))
+	s := &Sheet{
+		name:    name,
+		hash:    hex.EncodeToString(sum[:])[:8],
+		classes: make(map[string]string),
+	}
+	scoped := make([]Rule, len(rules))
+	for i, r := range rules {
+		scoped[i] = r.scoped(func(class string) string {
+			if n, ok := s.classes[class]; ok {
+				return n
+			}
+			n := class + "_" + s.hash
+			if name != "" {
+				n = name + "_" + n
+			}
+			s.classes[class] = n
+			return n
+		})
+	}
+	s.css = Render(scoped...)
+	return s
+}
+
+// Hash returns the hash used when generating class names for this sheet
+func (s *Sheet) Hash() string {
+	return s.hash
+}
+
+// CSS returns the content of the sheet with all class names replaced
+func (s *Sheet) CSS() string {
+	return s.css
+}
+
+// Has returns true if the class is used in this sheet
+func (s *Sheet) Has(class string) bool {
+	_, ok := s.classes[class]
+	return ok
+}
+
+// ClassName returns the generated name for the supplied class. Classes not used in this sheet are returned as-is
+func (s *Sheet) ClassName(class string) string {
+	if n, ok := s.classes[class]; ok {
+		return n
+	}
+	return class
+}
+
+// ClassNames returns the generated names for the supplied classes separated by a space
+func (s *Sheet) ClassNames(classes ...string) string {
+	names := make([]string, len(classes))
+	for i, c := range classes {
+		names[i] = s.ClassName(c)
+	}
+	return strings.Join(names, " ")
+}
+
+// Use registers the sheet so that it's emitted in the head of the document. If the sheet is part of a Bundle
+// then a link to the bundle is emitted instead
+func (s *Sheet) Use() h.Node {
+	if s.bundle != nil {
+		return s.bundle.Use()
+	}
+	return h.UseHead("sheet:"+s.hash, h.Style(h.Text(s.css)))
+}
+
+// Class emits a class attribute containing the generated names for the supplied classes and registers the sheet
+// so that it's emitted in the head of the document
+func (s *Sheet) Class(classes ...string) h.Node {
+	return h.Join(
+		s.Use(),
+		a.Class(s.ClassNames(classes...)),
+	)
+}
+
+// Bundle combines multiple sheets into a single external stylesheet. The stylesheet has a fingerprinted URL, which
+// means that it can be cached by the browser forever. Sheets that are part of a bundle are linked instead of emitted
+// in a style tag.
+//
+// Example:
+//
+//	var bundle = css.NewBundle("/static/components.css", buttonSheet, cardSheet)
+//
+//	func main() {
+//	  http.Handle(bundle.URL(), bundle)
+//	}
+type Bundle struct {
+	url     string
+	etag    string
+	content []byte
+}
+
+// NewBundle creates a bundle of the supplied sheets. The URL of the bundle is the supplied path with the
+// hash of the content added before the file extension. A sheet can only be part of one bundle. Panics if any of
+// the sheets already is part of a bundle
+func NewBundle(urlPath string, sheets ...*Sheet) *Bundle {
+	content := bytes.Buffer{}
+	for _, s := range sheets {
+		if s.bundle != nil {
+			panic(fmt.Errorf("%w: %s is part of %s", ErrBundled, s.name, s.bundle.url))
+		}
+		content.WriteString(s.css)
+		content.WriteByte('\n')
+	}
+	sum := sha256.Sum256(content.Bytes())
+	hash := hex.EncodeToString(sum[:])[:8]
+	ext := path.Ext(urlPath)
+	b := &Bundle{
+		url:     strings.TrimSuffix(urlPath, ext) + "." + hash + ext,
+		etag:    `"` + hash + `"`,
+		content: content.Bytes(),
+	}
+	for _, s := range sheets {
+		s.bundle = b
+	}
+	return b
+}
+
+// URL returns the fingerprinted URL of this bundle
+func (b *Bundle) URL() string {
+	return b.url
+}
+
+// Use registers a link to this bundle in the head of the document
+func (b *Bundle) Use() h.Node {
+	return h.UseStylesheet(b.url)
+}
+
+// ServeHTTP serves the content of the bundle. Since the URL changes if the content changes the browser
+// is allowed to cache the content forever
+func (b *Bundle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", b.etag)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(b.content))
+}
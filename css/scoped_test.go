@@ -0,0 +1,57 @@
+package css
+
+import (
+	"errors"
+	"testing"
+)
+
+// panicOf returns the error that the supplied function panics with
+func panicOf(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err, _ = r.(error)
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestSheetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"", true},
+		{"button", true},
+		{"Card_list-2", true},
+		{"2col", false},
+		{"-x", false},
+		{"a b", false},
+		{"a.b", false},
+		{"a{}", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := panicOf(func() {
+				NewSheet(test.name, Select(".a", Display.Set(Block)))
+			})
+			if test.valid && err != nil {
+				t.Errorf("expected %q to be valid but was %v", test.name, err)
+			}
+			if !test.valid && !errors.Is(err, ErrInvalidName) {
+				t.Errorf("expected %v but was %v", ErrInvalidName, err)
+			}
+		})
+	}
+}
+
+func TestSheetInTwoBundles(t *testing.T) {
+	s := NewSheet("button", Select(".primary", Display.Set(Block)))
+	b := NewBundle("/static/a.css", s)
+	if err := panicOf(func() { NewBundle("/static/b.css", s) }); !errors.Is(err, ErrBundled) {
+		t.Errorf("expected %v but was %v", ErrBundled, err)
+	}
+	if s.bundle != b {
+		t.Errorf("expected the sheet to stay in the first bundle but was %s", s.bundle.URL())
+	}
+}
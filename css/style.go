@@ -0,0 +1,244 @@
+package css
+
+import (
+	"errors"
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"strings"
+)
+
+// ErrUnsafeSelector is returned when a selector, or a media query, contains characters that can break out of the rule
+// or the style tag
+var ErrUnsafeSelector = errors.New("css: unsafe selector")
+
+// Fragment is one or more declarations that can be merged together into a single style
+type Fragment interface {
+	declarations() []Declaration
+}
+
+// Declaration is a single property-value pair
+type Declaration struct {
+	property  Property
+	value     string
+	important bool
+}
+
+func (d Declaration) declarations() []Declaration {
+	return []Declaration{d}
+}
+
+// Important returns a copy of the declaration marked as !important
+func (d Declaration) Important() Declaration {
+	d.important = true
+	return d
+}
+
+// Valid returns false if either the property or the value is invalid. Invalid declarations are not emitted
+func (d Declaration) Valid() bool {
+	return d.value != "" && d.property.Valid()
+}
+
+func (d Declaration) write(sb *strings.Builder) {
+	sb.WriteString(string(d.property))
+	sb.WriteByte(':')
+	sb.WriteString(d.value)
+	if d.important {
+		sb.WriteString("!important")
+	}
+}
+
+// Style is a list of declarations in which each property is only found once
+type Style []Declaration
+
+func (s Style) declarations() []Declaration {
+	return s
+}
+
+// Merge merges the supplied fragments into a single style. If a property is declared more than once then the last
+// declaration wins, as it would in the browser. Invalid declarations are removed
+func Merge(f ...Fragment) Style {
+	var result Style
+	index := make(map[Property]int)
+	for _, ff := range f {
+		if ff == nil {
+			continue
+		}
+		for _, d := range ff.declarations() {
+			if !d.Valid() {
+				continue
+			}
+			if i, ok := index[d.property]; ok {
+				result = append(result[:i], result[i+1:]...)
+				for p, j := range index {
+					if j > i {
+						index[p] = j - 1
+					}
+				}
+			}
+			index[d.property] = len(result)
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// String returns the style as it's written in a style attribute
+func (s Style) String() string {
+	sb := strings.Builder{}
+	for i, d := range Merge(s) {
+		if i > 0 {
+			sb.WriteByte(';')
+		}
+		d.write(&sb)
+	}
+	return sb.String()
+}
+
+// Inline merges the supplied fragments and emits them as a style attribute
+//
+// Example:
+//
+//	h.Div(
+//	  css.Inline(
+//	    css.Display.Set(css.Flex),
+//	    css.Gap.Set(css.Rem(1)),
+//	  ),
+//	)
+func Inline(f ...Fragment) h.Node {
+	s := Merge(f...)
+	if len(s) == 0 {
+		return h.Empty()
+	}
+	// quotes in strings are the only characters that needs to be escaped in an attribute. Everything else has
+	// already been escaped when the values were created
+	return a.Style(strings.ReplaceAll(s.String(), `"`, "&quot;"))
+}
+
+// Rule is a single rule in a stylesheet
+type Rule interface {
+	write(sb *strings.Builder)
+	// scoped returns a copy of the rule where all class selectors are renamed
+	scoped(rename func(class string) string) Rule
+	// validate returns an error if the rule can't be written
+	validate() error
+}
+
+// safeSelector returns false if the selector, or media query, can break out of the rule or the style tag.
+// Other characters, such as the > in a child combinator, are allowed
+func safeSelector(s string) bool {
+	return strings.TrimSpace(s) != "" && !strings.ContainsAny(s, "{};") && !strings.Contains(s, "</")
+}
+
+// validateSelector returns ErrUnsafeSelector if the selector isn't safe
+func validateSelector(s string) error {
+	if !safeSelector(s) {
+		return fmt.Errorf("%w: %q", ErrUnsafeSelector, s)
+	}
+	return nil
+}
+
+type selectRule struct {
+	selector string
+	style    Style
+}
+
+func (r selectRule) write(sb *strings.Builder) {
+	if !safeSelector(r.selector) || len(r.style) == 0 {
+		return
+	}
+	sb.WriteString(r.selector)
+	sb.WriteByte('{')
+	sb.WriteString(r.style.String())
+	sb.WriteByte('}')
+}
+
+func (r selectRule) validate() error {
+	return validateSelector(r.selector)
+}
+
+func (r selectRule) scoped(rename func(class string) string) Rule {
+	r.selector = renameClasses(r.selector, rename)
+	return r
+}
+
+// Select creates a rule for the supplied selector. The rule is not emitted if the selector contains
+// characters that might break out of the rule, such as braces. Use Validate to find such rules
+func Select(selector string, f ...Fragment) Rule {
+	return selectRule{
+		selector: selector,
+		style:    Merge(f...),
+	}
+}
+
+type mediaRule struct {
+	query string
+	rules []Rule
+}
+
+func (r mediaRule) write(sb *strings.Builder) {
+	if !safeSelector(r.query) {
+		return
+	}
+	sb.WriteString("@media ")
+	sb.WriteString(r.query)
+	sb.WriteByte('{')
+	for _, rr := range r.rules {
+		rr.write(sb)
+	}
+	sb.WriteByte('}')
+}
+
+func (r mediaRule) validate() error {
+	if err := validateSelector(r.query); err != nil {
+		return err
+	}
+	return Validate(r.rules...)
+}
+
+func (r mediaRule) scoped(rename func(class string) string) Rule {
+	rules := make([]Rule, len(r.rules))
+	for i, rr := range r.rules {
+		rules[i] = rr.scoped(rename)
+	}
+	r.rules = rules
+	return r
+}
+
+// Media creates a media query containing the supplied rules
+func Media(query string, rules ...Rule) Rule {
+	return mediaRule{
+		query: query,
+		rules: rules,
+	}
+}
+
+// Validate returns an error, wrapping ErrUnsafeSelector, if any of the rules or media queries have a selector that
+// can break out of the rule or the style tag
+func Validate(rules ...Rule) error {
+	errs := make([]error, 0, len(rules))
+	for _, r := range rules {
+		if err := r.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Render returns the supplied rules as a stylesheet. Rules with an unsafe selector are skipped. Use Validate to
+// find them
+func Render(rules ...Rule) string {
+	sb := strings.Builder{}
+	for _, r := range rules {
+		r.write(&sb)
+	}
+	return sb.String()
+}
+
+// Stylesheet emits the supplied rules in a style tag. The rendering fails if any of the rules has an unsafe selector
+func Stylesheet(rules ...Rule) h.Node {
+	if err := Validate(rules...); err != nil {
+		return h.Fail(err)
+	}
+	return h.Style(h.Text(Render(rules...)))
+}
@@ -0,0 +1,39 @@
+package css
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelectors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		expected string
+		err      bool
+	}{
+		{"class", Select(".a", Display.Set(Block)), ".a{display:block}", false},
+		{"child combinator", Select("ul > li", Display.Set(Block)), "ul > li{display:block}", false},
+		{"sibling combinators", Select("h1 + p ~ p", Display.Set(Block)), "h1 + p ~ p{display:block}", false},
+		{"escaped class", Select(`.a\:b`, Display.Set(Block)), `.a\:b{display:block}`, false},
+		{"media", Media("(min-width: 40rem)", Select("a > b", Display.Set(Block))),
+			"@media (min-width: 40rem){a > b{display:block}}", false},
+		{"empty", Select("", Display.Set(Block)), "", true},
+		{"brace", Select("a{}b", Display.Set(Block)), "", true},
+		{"semicolon", Select("a;b", Display.Set(Block)), "", true},
+		{"end tag", Select("</style><script>", Display.Set(Block)), "", true},
+		{"unsafe media", Media("screen{", Select("a", Display.Set(Block))), "", true},
+		{"unsafe rule in media", Media("screen", Select("a}", Display.Set(Block))), "@media screen{}", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := Render(test.rule); actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+			err := Validate(test.rule)
+			if test.err != errors.Is(err, ErrUnsafeSelector) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
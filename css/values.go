@@ -0,0 +1,234 @@
+package css
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Value is a single, already escaped, CSS value. Values can only be created using the functions and constants
+// in this package, which is how we make sure that a value can't break out of a declaration
+type Value struct {
+	s string
+}
+
+// String returns the value as it's written in a stylesheet
+func (v Value) String() string {
+	return v.s
+}
+
+// Valid returns false if the value was created from invalid input. Declarations with invalid values are not emitted
+func (v Value) Valid() bool {
+	return v.s != ""
+}
+
+var (
+	Auto         = Value{"auto"}
+	None         = Value{"none"}
+	Inherit      = Value{"inherit"}
+	Initial      = Value{"initial"}
+	Unset        = Value{"unset"}
+	Normal       = Value{"normal"}
+	Zero         = Value{"0"}
+	Block        = Value{"block"}
+	InlineValue  = Value{"inline"}
+	InlineBlock  = Value{"inline-block"}
+	Flex         = Value{"flex"}
+	InlineFlex   = Value{"inline-flex"}
+	Grid         = Value{"grid"}
+	Contents     = Value{"contents"}
+	Hidden       = Value{"hidden"}
+	Visible      = Value{"visible"}
+	Scroll       = Value{"scroll"}
+	Static       = Value{"static"}
+	Relative     = Value{"relative"}
+	Absolute     = Value{"absolute"}
+	Fixed        = Value{"fixed"}
+	Sticky       = Value{"sticky"}
+	Row          = Value{"row"}
+	Column       = Value{"column"}
+	Wrap         = Value{"wrap"}
+	NoWrap       = Value{"nowrap"}
+	Center       = Value{"center"}
+	Start        = Value{"start"}
+	End          = Value{"end"}
+	FlexStart    = Value{"flex-start"}
+	FlexEnd      = Value{"flex-end"}
+	Stretch      = Value{"stretch"}
+	SpaceBetween = Value{"space-between"}
+	SpaceAround  = Value{"space-around"}
+	SpaceEvenly  = Value{"space-evenly"}
+	Left         = Value{"left"}
+	Right        = Value{"right"}
+	Top          = Value{"top"}
+	Bottom       = Value{"bottom"}
+	Bold         = Value{"bold"}
+	Italic       = Value{"italic"}
+	Underline    = Value{"underline"}
+	Uppercase    = Value{"uppercase"}
+	Lowercase    = Value{"lowercase"}
+	Pointer      = Value{"pointer"}
+	Solid        = Value{"solid"}
+	Dashed       = Value{"dashed"}
+	Dotted       = Value{"dotted"}
+	BorderBox    = Value{"border-box"}
+	ContentBox   = Value{"content-box"}
+	Cover        = Value{"cover"}
+	Contain      = Value{"contain"}
+	Transparent  = Value{"transparent"}
+	CurrentColor = Value{"currentcolor"}
+)
+
+// number formats a number without any unnecessary decimals
+func number(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func unit(v float64, u string) Value {
+	return Value{number(v) + u}
+}
+
+// Num is a number without a unit, for example a line-height or a z-index
+func Num(v float64) Value {
+	return Value{number(v)}
+}
+
+func Px(v float64) Value      { return unit(v, "px") }
+func Em(v float64) Value      { return unit(v, "em") }
+func Rem(v float64) Value     { return unit(v, "rem") }
+func Percent(v float64) Value { return unit(v, "%") }
+func Vw(v float64) Value      { return unit(v, "vw") }
+func Vh(v float64) Value      { return unit(v, "vh") }
+func Ch(v float64) Value      { return unit(v, "ch") }
+func Fr(v float64) Value      { return unit(v, "fr") }
+func Deg(v float64) Value     { return unit(v, "deg") }
+func Ms(v float64) Value      { return unit(v, "ms") }
+func Sec(v float64) Value     { return unit(v, "s") }
+
+// Hex creates a color from a hex string such as #fff or #ff00ff80. The value is invalid if the string isn't a hex color
+func Hex(color string) Value {
+	if len(color) == 0 || color[0] != '#' {
+		return Value{}
+	}
+	switch len(color) {
+	case 4, 5, 7, 9:
+	default:
+		return Value{}
+	}
+	for _, c := range color[1:] {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return Value{}
+		}
+	}
+	return Value{strings.ToLower(color)}
+}
+
+// RGB creates a color from its red, green and blue components
+func RGB(r, g, b uint8) Value {
+	return Value{"rgb(" + strconv.Itoa(int(r)) + "," + strconv.Itoa(int(g)) + "," + strconv.Itoa(int(b)) + ")"}
+}
+
+// RGBA creates a color from its red, green and blue components and an alpha between 0 and 1
+func RGBA(r, g, b uint8, alpha float64) Value {
+	alpha = math.Max(0, math.Min(1, alpha))
+	return Value{"rgba(" + strconv.Itoa(int(r)) + "," + strconv.Itoa(int(g)) + "," + strconv.Itoa(int(b)) + "," + number(alpha) + ")"}
+}
+
+// HSL creates a color from a hue in degrees and the saturation and lightness in percent
+func HSL(hue, saturation, lightness float64) Value {
+	return Value{"hsl(" + number(hue) + "," + number(saturation) + "%," + number(lightness) + "%)"}
+}
+
+// isIdent returns true if the supplied string is a valid CSS identifier that doesn't need any escaping
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_', c >= 0x80:
+		case c == '-':
+		case '0' <= c && c <= '9':
+			if i == 0 || (i == 1 && s[0] == '-') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Keyword creates a value from an identifier, for example a named color such as "rebeccapurple". The value is
+// invalid if the keyword isn't a valid CSS identifier
+func Keyword(k string) Value {
+	if !isIdent(k) {
+		return Value{}
+	}
+	return Value{k}
+}
+
+// Var references a custom property, such as --primary-color. The value is invalid if the name isn't a custom property
+func Var(name string, fallback ...Value) Value {
+	if !strings.HasPrefix(name, "--") || !isIdent(name) {
+		return Value{}
+	}
+	if len(fallback) == 0 {
+		return Value{"var(" + name + ")"}
+	}
+	return Value{"var(" + name + "," + join(fallback, " ") + ")"}
+}
+
+// String creates a quoted CSS string, for example to be used by the content property. The content is escaped
+func String(s string) Value {
+	return Value{`"` + escape(s) + `"`}
+}
+
+// URL creates an url() value. The url is escaped
+func URL(u string) Value {
+	return Value{`url("` + escape(u) + `")`}
+}
+
+// List joins multiple values with a comma, for example a list of font families
+func List(v ...Value) Value {
+	return Value{join(v, ",")}
+}
+
+// Spaced joins multiple values with a space, for example the values of the margin property
+func Spaced(v ...Value) Value {
+	return Value{join(v, " ")}
+}
+
+// join joins the values. Returns an empty string if any of the values are invalid
+func join(v []Value, sep string) string {
+	sb := strings.Builder{}
+	for i, vv := range v {
+		if !vv.Valid() {
+			return ""
+		}
+		if i > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(vv.s)
+	}
+	return sb.String()
+}
+
+// escape escapes the content of a CSS string so that it's safe to use both in a style attribute and in a style tag
+func escape(s string) string {
+	sb := strings.Builder{}
+	for _, c := range s {
+		switch {
+		case c < 0x20, c == 0x7f, c == '"', c == '\'', c == '\\', c == '<', c == '>', c == '&':
+			sb.WriteByte('\\')
+			sb.WriteString(strconv.FormatInt(int64(c), 16))
+			sb.WriteByte(' ')
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
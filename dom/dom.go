@@ -0,0 +1,172 @@
+package dom
+
+import (
+	"bytes"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"io"
+	"strings"
+)
+
+// NodeType is the type of node in the tree
+type NodeType int
+
+const (
+	ElementNode NodeType = iota
+	TextNode
+	CommentNode
+	DoctypeNode
+)
+
+// Attr is a single attribute on an element. The value is unescaped
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Node is a single node in a parsed HTML tree. All text and attribute values are unescaped, except for the content
+// of raw text elements such as script and style
+type Node struct {
+	Type NodeType
+	// Tag is the lower-case name of the element
+	Tag   string
+	Attrs []Attr
+	// Data is the text of a text node, a comment or a doctype
+	Data     string
+	Children []*Node
+}
+
+// Attr returns the value of the supplied attribute and a boolean that indicates if the element has the attribute
+func (n *Node) Attr(key string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// voidElements are elements that can't have any children
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTextElements are elements where the content is not parsed as HTML
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+// IsVoid returns true if the supplied element can't have any children
+func IsVoid(tag string) bool {
+	return voidElements[tag]
+}
+
+// IsRawText returns true if the content of the supplied element is written without escaping
+func IsRawText(tag string) bool {
+	return tag == "script" || tag == "style"
+}
+
+// WriteTo writes the node, and its children, as HTML
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	bb := bytes.Buffer{}
+	n.write(&bb)
+	return bb.WriteTo(w)
+}
+
+// HTML returns the node, and its children, as HTML
+func (n *Node) HTML() string {
+	sb := bytes.Buffer{}
+	n.write(&sb)
+	return sb.String()
+}
+
+func (n *Node) write(bb *bytes.Buffer) {
+	switch n.Type {
+	case TextNode:
+		bb.WriteString(EscapeText(n.Data))
+	case CommentNode:
+		bb.WriteString("<!--")
+		bb.WriteString(strings.ReplaceAll(n.Data, "--", "- -"))
+		bb.WriteString("-->")
+	case DoctypeNode:
+		bb.WriteString("<!DOCTYPE ")
+		bb.WriteString(n.Data)
+		bb.WriteByte('>')
+	case ElementNode:
+		bb.WriteByte('<')
+		bb.WriteString(n.Tag)
+		for _, a := range n.Attrs {
+			bb.WriteByte(' ')
+			bb.WriteString(a.Key)
+			bb.WriteString(`="`)
+			bb.WriteString(html.EscapeString(a.Value))
+			bb.WriteByte('"')
+		}
+		if IsVoid(n.Tag) {
+			bb.WriteString("/>")
+			return
+		}
+		bb.WriteByte('>')
+		for _, c := range n.Children {
+			if c.Type == TextNode && IsRawText(n.Tag) {
+				bb.WriteString(c.Data)
+				continue
+			}
+			c.write(bb)
+		}
+		bb.WriteString("</")
+		bb.WriteString(n.Tag)
+		bb.WriteByte('>')
+	}
+}
+
+// EscapeText escapes the characters in a text node that have a special meaning in HTML
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Node converts the node, and its children, into a gohtml node. All text and attribute values are escaped
+func (n *Node) Node() h.Node {
+	switch n.Type {
+	case TextNode:
+		return h.Text(EscapeText(n.Data))
+	case CommentNode:
+		return h.Comment(h.Text(strings.ReplaceAll(n.Data, "--", "- -")))
+	case DoctypeNode:
+		return h.Empty()
+	}
+	c := make([]h.Node, 0, len(n.Attrs)+len(n.Children))
+	for _, a := range n.Attrs {
+		c = append(c, h.Attrib(a.Key, html.EscapeString(a.Value)))
+	}
+	if IsVoid(n.Tag) {
+		return h.TagEmpty(n.Tag, c...)
+	}
+	for _, cc := range n.Children {
+		if cc.Type == TextNode && IsRawText(n.Tag) {
+			c = append(c, h.Text(cc.Data))
+			continue
+		}
+		c = append(c, cc.Node())
+	}
+	return h.Tag(n.Tag, c...)
+}
+
+// Nodes converts all supplied nodes into a single gohtml node
+func Nodes(nodes []*Node) h.Node {
+	c := make([]h.Node, len(nodes))
+	for i, n := range nodes {
+		c[i] = n.Node()
+	}
+	return h.Join(c...)
+}
+
+// Render renders the supplied gohtml node and parses the result into a tree
+func Render(n h.Node) []*Node {
+	bb := bytes.Buffer{}
+	_ = n(0, &bb)
+	return Parse(bb.String())
+}
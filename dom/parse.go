@@ -0,0 +1,242 @@
+package dom
+
+import (
+	"html"
+	"strings"
+)
+
+// closesSelf are elements that are implicitly closed when an element of the same type is opened
+var closesSelf = map[string]bool{
+	"li": true, "p": true, "option": true, "tr": true, "td": true, "th": true, "dt": true, "dd": true,
+}
+
+// parser is a small and forgiving HTML parser. It doesn't implement the full HTML5 parsing algorithm, but it can
+// parse the output of gohtml and most hand-written HTML
+type parser struct {
+	s     string
+	pos   int
+	root  Node
+	stack []*Node
+}
+
+// Parse parses a HTML fragment into a list of nodes. The parser never fails. Unknown or broken markup is
+// treated as text and elements that are not closed are closed at the end of the input
+func Parse(s string) []*Node {
+	p := &parser{s: s}
+	p.stack = []*Node{&p.root}
+	for p.pos < len(p.s) {
+		if p.s[p.pos] == '<' && p.tag() {
+			continue
+		}
+		p.text()
+	}
+	return p.root.Children
+}
+
+// advance moves the position forward, but never past the end of the input
+func (p *parser) advance(n int) {
+	p.pos = min(p.pos+n, len(p.s))
+}
+
+func (p *parser) top() *Node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) add(n *Node) {
+	top := p.top()
+	// merge consecutive text nodes
+	if n.Type == TextNode && len(top.Children) > 0 {
+		if last := top.Children[len(top.Children)-1]; last.Type == TextNode {
+			last.Data += n.Data
+			return
+		}
+	}
+	top.Children = append(top.Children, n)
+}
+
+// text reads text until the next tag
+func (p *parser) text() {
+	end := strings.IndexByte(p.s[p.pos+1:], '<')
+	if end == -1 {
+		end = len(p.s)
+	} else {
+		end += p.pos + 1
+	}
+	p.add(&Node{Type: TextNode, Data: html.UnescapeString(p.s[p.pos:end])})
+	p.pos = end
+}
+
+// tag tries to read a tag at the current position. Returns false if the content isn't a tag
+func (p *parser) tag() bool {
+	rest := p.s[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, "<!--"):
+		end := strings.Index(rest[4:], "-->")
+		if end == -1 {
+			p.add(&Node{Type: CommentNode, Data: rest[4:]})
+			p.pos = len(p.s)
+		} else {
+			p.add(&Node{Type: CommentNode, Data: rest[4 : 4+end]})
+			p.advance(4 + end + 3)
+		}
+		return true
+	case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+		end := strings.IndexByte(rest, '>')
+		if end == -1 {
+			end = len(rest)
+		}
+		content := rest[2:end]
+		if len(content) >= 7 && strings.EqualFold(content[:7], "doctype") {
+			p.add(&Node{Type: DoctypeNode, Data: strings.TrimSpace(content[7:])})
+		}
+		p.advance(end + 1)
+		return true
+	case strings.HasPrefix(rest, "</"):
+		if len(rest) < 3 || !isLetter(rest[2]) {
+			return false
+		}
+		end := strings.IndexByte(rest, '>')
+		if end == -1 {
+			p.pos = len(p.s)
+			return true
+		}
+		name := strings.ToLower(strings.TrimSpace(rest[2:end]))
+		if i := strings.IndexAny(name, " \t\r\n/"); i != -1 {
+			name = name[:i]
+		}
+		p.advance(end + 1)
+		p.close(name)
+		return true
+	}
+	if len(rest) < 2 || !isLetter(rest[1]) {
+		return false
+	}
+	p.pos++
+	p.open()
+	return true
+}
+
+// close closes the last open element with the supplied name. The end tag is ignored if no such element is open
+func (p *parser) close(name string) {
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if p.stack[i].Tag == name {
+			p.stack = p.stack[:i]
+			return
+		}
+	}
+}
+
+// open reads a start tag, and its attributes, right after the <
+func (p *parser) open() {
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '>' && p.s[p.pos] != '/' {
+		p.pos++
+	}
+	n := &Node{Type: ElementNode, Tag: strings.ToLower(p.s[start:p.pos])}
+	selfClosing := false
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case isSpace(c):
+			p.pos++
+			continue
+		case c == '>':
+			p.pos++
+		case c == '/':
+			p.pos++
+			if p.pos < len(p.s) && p.s[p.pos] == '>' {
+				selfClosing = true
+			}
+			continue
+		default:
+			p.attribute(n)
+			continue
+		}
+		break
+	}
+
+	if closesSelf[n.Tag] && p.top().Tag == n.Tag {
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+	p.add(n)
+	if selfClosing || IsVoid(n.Tag) {
+		return
+	}
+	if rawTextElements[n.Tag] {
+		p.rawText(n)
+		return
+	}
+	p.stack = append(p.stack, n)
+}
+
+// attribute reads a single attribute
+func (p *parser) attribute(n *Node) {
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && !strings.ContainsRune("/>=", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	// make sure that we always move forward
+	if p.pos == start {
+		p.advance(1)
+		return
+	}
+	a := Attr{Key: strings.ToLower(p.s[start:p.pos])}
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos < len(p.s) && p.s[p.pos] == '=' {
+		p.pos++
+		for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+			p.pos++
+		}
+		if p.pos < len(p.s) && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+			q := p.s[p.pos]
+			end := strings.IndexByte(p.s[p.pos+1:], q)
+			if end == -1 {
+				// the value is not terminated, so it continues to the end of the input
+				end = len(p.s) - p.pos - 1
+			}
+			a.Value = html.UnescapeString(p.s[p.pos+1 : p.pos+1+end])
+			p.advance(end + 2)
+		} else {
+			vs := p.pos
+			for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '>' {
+				p.pos++
+			}
+			a.Value = html.UnescapeString(p.s[vs:p.pos])
+		}
+	}
+	if _, exists := n.Attr(a.Key); !exists {
+		n.Attrs = append(n.Attrs, a)
+	}
+}
+
+// rawText reads the content of a raw text element until its end tag
+func (p *parser) rawText(n *Node) {
+	lower := strings.ToLower(p.s[p.pos:])
+	end := strings.Index(lower, "</"+n.Tag)
+	if end == -1 {
+		end = len(lower)
+	}
+	if content := p.s[p.pos : p.pos+end]; content != "" {
+		if IsRawText(n.Tag) {
+			n.Children = append(n.Children, &Node{Type: TextNode, Data: content})
+		} else {
+			n.Children = append(n.Children, &Node{Type: TextNode, Data: html.UnescapeString(content)})
+		}
+	}
+	p.advance(end)
+	if close := strings.IndexByte(p.s[p.pos:], '>'); close != -1 {
+		p.advance(close + 1)
+	} else {
+		p.pos = len(p.s)
+	}
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
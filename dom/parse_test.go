@@ -0,0 +1,104 @@
+package dom
+
+import (
+	"strings"
+	"testing"
+)
+
+func render(nodes []*Node) string {
+	sb := strings.Builder{}
+	for _, n := range nodes {
+		sb.WriteString(n.HTML())
+	}
+	return sb.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"text", "Hello &amp; world", "Hello &amp; world"},
+		{"element", `<p class="a">Hello</p>`, `<p class="a">Hello</p>`},
+		{"unquoted attribute", `<p class=a>x</p>`, `<p class="a">x</p>`},
+		{"void element", `<br><img src="/x.png">`, `<br/><img src="/x.png"/>`},
+		{"self closing", `<div/>x`, `<div></div>x`},
+		{"duplicate attribute", `<p id="a" id="b"></p>`, `<p id="a"></p>`},
+		{"implicitly closed", `<ul><li>a<li>b</ul>`, `<ul><li>a</li><li>b</li></ul>`},
+		{"unclosed element", `<div><p>x`, `<div><p>x</p></div>`},
+		{"unknown end tag", `<p>x</span></p>`, `<p>x</p>`},
+		{"comment", `<!-- x -->y`, `<!-- x -->y`},
+		{"doctype", `<!DOCTYPE html><p></p>`, `<!DOCTYPE html><p></p>`},
+		{"script", `<script>if (a < b) {}</script>`, `<script>if (a < b) {}</script>`},
+		{"not a tag", `a < b <3`, `a &lt; b &lt;3`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := render(Parse(test.input))
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"unterminated double quote in script", `<script a="`, `<script a=""></script>`},
+		{"unterminated single quote in textarea", `<textarea x='`, `<textarea x=""></textarea>`},
+		{"unterminated value in style", `<style a="x`, `<style a="x"></style>`},
+		{"unterminated value", `<p a="x`, `<p a="x"></p>`},
+		{"unterminated tag", `<p a`, `<p a=""></p>`},
+		{"unterminated end tag", `<p>x</p`, `<p>x</p>`},
+		{"unterminated comment", `<!-- x`, `<!-- x-->`},
+		{"unterminated doctype", `<!`, ``},
+		{"unterminated declaration", `<!DOCTYPE html`, `<!DOCTYPE html>`},
+		{"unterminated raw text", `<script>alert(1)`, `<script>alert(1)</script>`},
+		{"lone less than", `<`, `&lt;`},
+		{"end tag without name", `</>`, `&lt;/&gt;`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := render(Parse(test.input))
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	inputs := []string{
+		`<!DOCTYPE html><html lang="en"><head><title>x</title><style a="b">p{}</style></head>` +
+			`<body><!-- c --><p class='x' id=y>Hello <b>world</b></p><br/><script src="/a.js"></script>` +
+			`<textarea name="t">a &amp; b</textarea></body></html>`,
+		`<a href="javascript:alert(1)" onclick='x()'>link</a><? x ?><!x>`,
+	}
+	for _, input := range inputs {
+		for i := 0; i <= len(input); i++ {
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("Parse(%q) panicked: %v", input[:i], r)
+					}
+				}()
+				Parse(input[:i])
+			}()
+		}
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(`<script a="`)
+	f.Add(`<textarea x='`)
+	f.Add(`<style a="x`)
+	f.Add(`<p a=b c='d' e="f">x</p><!--y-->`)
+	f.Fuzz(func(t *testing.T, input string) {
+		Parse(input)
+	})
+}
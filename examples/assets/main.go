@@ -0,0 +1,53 @@
+package main
+
+import (
+	"embed"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/assets"
+	"github.com/westcoastcode-se/gohtml/h"
+	"io/fs"
+	"log"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// files contains all files in the static directory. The app.css file has a precompressed variant, app.css.gz,
+// that is served to all browsers that accept gzip
+var files = assets.MustNew(sub(static, "static"), assets.Props{Prefix: "/static/"})
+
+func sub(fsys fs.FS, dir string) fs.FS {
+	s, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	_, _ = h.Html(a.Lang("en"),
+		h.Head(
+			h.Meta(a.Charset("UTF-8")),
+			h.Title("Example: Assets"),
+			// Emits a fingerprinted URL, such as /static/app.1a2b3c4d.css
+			h.Link(a.Rel(a.RelStylesheet), files.Href("app.css")),
+			// Small files are inlined in the document instead of being linked. The threshold is configured
+			// using the InlineThreshold property
+			files.Script("app.js"),
+		),
+		h.Body(
+			h.H1(h.Text("Assets")),
+			h.P(a.ID("greeting")),
+		),
+	)(w)
+}
+
+func main() {
+	http.HandleFunc("/", index)
+	http.Handle("/static/", files)
+	err := http.ListenAndServe(":8080", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,3 @@
+document.addEventListener("DOMContentLoaded", function () {
+    document.getElementById("greeting").textContent = "Hello from an embedded script";
+});
@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/csp"
 	. "github.com/westcoastcode-se/gohtml/h"
 	"io"
 	"log"
@@ -39,30 +40,13 @@ func CDN(path string) chan []byte {
 	return result
 }
 
-// EmitJavascript by making a server-server request to a CDN
-func EmitJavascript(path string) Node {
+// EmitCDN emits the content of a file on a CDN by making a server-server request
+func EmitCDN(path string) Node {
 	return EmitChannel(func() chan Node {
 		ch := make(chan Node)
 		go func() {
 			cdn := CDN(path)
-			ch <- Script(
-				Bytes(<-cdn),
-			)
-			close(ch)
-		}()
-		return ch
-	})
-}
-
-// EmitCSS by making a server-server request to a CDN
-func EmitCSS(path string) Node {
-	return EmitChannel(func() chan Node {
-		ch := make(chan Node)
-		go func() {
-			cdn := CDN(path)
-			ch <- Style(
-				Bytes(<-cdn),
-			)
+			ch <- Bytes(<-cdn)
 			close(ch)
 		}()
 		return ch
@@ -80,13 +64,18 @@ func index(w http.ResponseWriter, r *http.Request) {
 			// Add a meta header tag with the attribute charset="UTF-8"
 			Meta(a.Charset("UTF-8")),
 			Title("My Title"),
-			// Cache the Materialize CSS in-memory on the server and serve the result backed into the response HTML
-			Cache(cacheStorage, "materialize_css", 10*time.Minute,
-				EmitCSS("https://cdnjs.cloudflare.com/ajax/libs/materialize/1.0.0/css/materialize.min.css"),
+			// Cache the Materialize CSS in-memory on the server and serve the result backed into the response HTML.
+			// Only the content is cached, which means that the style tag gets the nonce of the current request
+			Style(
+				Cache(cacheStorage, "materialize_css", 10*time.Minute,
+					EmitCDN("https://cdnjs.cloudflare.com/ajax/libs/materialize/1.0.0/css/materialize.min.css"),
+				),
 			),
 			// Cache the Materialize Javascript in-memory on the server and serve the result backed into the response HTML
-			Cache(cacheStorage, "materialize_js", 10*time.Minute,
-				EmitJavascript("https://cdnjs.cloudflare.com/ajax/libs/materialize/1.0.0/js/materialize.min.js"),
+			Script(
+				Cache(cacheStorage, "materialize_js", 10*time.Minute,
+					EmitCDN("https://cdnjs.cloudflare.com/ajax/libs/materialize/1.0.0/js/materialize.min.js"),
+				),
 			),
 		),
 		Body(
@@ -94,11 +83,14 @@ func index(w http.ResponseWriter, r *http.Request) {
 				Text("This is an example on how to make server-side calls to a CDN and cache the result"),
 			),
 		),
-	)(w)
+	)(WithContext(r.Context(), w))
 }
 
 func main() {
-	http.HandleFunc("/", index)
+	// The content security policy middleware adds a nonce to all inlined scripts and styles, which
+	// means that we don't need 'unsafe-inline' in the policy
+	secure := csp.Middleware(csp.Props{})
+	http.Handle("/", secure(http.HandlerFunc(index)))
 	err := http.ListenAndServe(":8080", nil)
 	if err != nil {
 		log.Fatal(err)
@@ -27,8 +27,8 @@ func (i Input) Label() h.Node {
 	)
 }
 
-// Node returns the actual input html node
-func (i Input) Node() h.Node {
+// Render returns the actual input html node
+func (i Input) Render() h.Node {
 	return h.Input(
 		a.ID(i.Id),
 		a.Type(i.Type),
@@ -36,7 +36,7 @@ func (i Input) Node() h.Node {
 	)
 }
 
-// Form represents a builder pattern that will, eventually, create a form with input fields
+// Form represents a component that will, eventually, create a form with input fields
 type Form struct {
 	Method     string
 	URL        string
@@ -44,8 +44,8 @@ type Form struct {
 	SubmitText string
 }
 
-// Build the entire form
-func (f Form) Build() h.Node {
+// Render the entire form
+func (f Form) Render() h.Node {
 	return h.Form(
 		a.Method(f.Method),
 		a.Action(f.URL),
@@ -56,7 +56,7 @@ func (f Form) Build() h.Node {
 						i.Label(),
 					),
 					h.Td(
-						i.Node(),
+						h.Render(i),
 					),
 				)
 			}),
@@ -65,7 +65,7 @@ func (f Form) Build() h.Node {
 					a.Colspan(2),
 					h.Input(
 						a.Type("submit"),
-						a.Value("Login"),
+						a.Value(f.SubmitText),
 					),
 				),
 			),
@@ -73,6 +73,21 @@ func (f Form) Build() h.Node {
 	)
 }
 
+// Panel is a component with a named "header" slot and default children. The header slot is optional
+type Panel struct {
+	h.Slots
+}
+
+// Render the panel and its slots
+func (p Panel) Render() h.Node {
+	return h.Div(a.Class("panel"),
+		p.SlotIf("header", h.Header),
+		h.Div(a.Class("panel-body"),
+			p.Default(h.Text("Nothing to show")),
+		),
+	)
+}
+
 func index(w http.ResponseWriter, r *http.Request) {
 	// generate the actual html
 	_, _ = h.Html(a.Lang("en"),
@@ -84,25 +99,30 @@ func index(w http.ResponseWriter, r *http.Request) {
 			h.H1(
 				h.Text("Example: Extensions"),
 			),
-			// Create a form using a custom builder
-			Form{
-				Method: http.MethodPost,
-				URL:    "/",
-				Fields: []Input{
-					{
-						Id:      "user",
-						Name:    "username",
-						Type:    "text",
-						Heading: "Username",
-					},
-					{
-						Id:      "pass",
-						Name:    "password",
-						Type:    "password",
-						Heading: "Password",
-					},
-				},
-			}.Build(),
+			// Create a form using a custom component and put it inside a panel
+			h.Render(Panel{
+				Slots: h.Slots{}.
+					Fill("header", h.H2(h.Text("Login"))).
+					With(h.Render(Form{
+						Method: http.MethodPost,
+						URL:    "/",
+						Fields: []Input{
+							{
+								Id:      "user",
+								Name:    "username",
+								Type:    "text",
+								Heading: "Username",
+							},
+							{
+								Id:      "pass",
+								Name:    "password",
+								Type:    "password",
+								Heading: "Password",
+							},
+						},
+						SubmitText: "Login",
+					})),
+			}),
 		),
 	)(w)
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"github.com/westcoastcode-se/gohtml/hx"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+var counter atomic.Int64
+
+// Counter is the part of the page that is replaced when the button is clicked
+func Counter() h.Node {
+	return h.Div(a.ID("counter"),
+		h.Textf("Clicked %d times", counter.Load()),
+	)
+}
+
+// Page is the full page, which is used when the request isn't made by htmx
+func Page(content h.Node) h.RootNode {
+	return h.Html(a.Lang("en"),
+		h.Head(
+			h.Meta(a.Charset("UTF-8")),
+			h.Title("Example: htmx"),
+			h.Script(a.Src("https://unpkg.com/htmx.org@2.0.3")),
+		),
+		h.Body(
+			h.Button(
+				hx.Post("/click"),
+				hx.Target("#counter"),
+				hx.Swap(hx.OuterHTML, hx.Settle(100*time.Millisecond)),
+				h.Text("Click me"),
+			),
+			content,
+		),
+	)
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	c := Counter()
+	_, _ = hx.Render(w, r, Page(c), c)
+}
+
+func click(w http.ResponseWriter, r *http.Request) {
+	counter.Add(1)
+	hx.SetTrigger(w, "clicked")
+	c := Counter()
+	// only the counter is written since this request is made by htmx
+	_, _ = hx.Render(w, r, Page(c), c)
+}
+
+func main() {
+	http.HandleFunc("/", index)
+	http.HandleFunc("POST /click", click)
+	err := http.ListenAndServe(":8080", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	. "github.com/westcoastcode-se/gohtml/h"
+	"log"
+	"net/http"
+)
+
+// Blocks that are part of the base layout. The layout and the pages refer to these variables, which means that
+// a misspelled block name results in a compile error
+var (
+	TitleBlock   = NewBlock("title", Title("Example: Layout"))
+	HeadBlock    = NewBlock("head")
+	ContentBlock = NewBlock("content", P(Text("Nothing to see here")))
+	ScriptsBlock = NewBlock("scripts")
+)
+
+// BaseLayout is the layout shared by all pages
+var BaseLayout = NewLayout(func(b Blocks) RootNode {
+	return Html(a.Lang("en"),
+		Head(
+			Meta(a.Charset("UTF-8")),
+			b.Block(TitleBlock),
+			b.Block(HeadBlock),
+		),
+		Body(
+			Nav(
+				A(a.Href("/"), Text("Home")),
+				A(a.Href("/about"), Text("About")),
+			),
+			Main(
+				b.Block(ContentBlock),
+			),
+			b.Block(ScriptsBlock),
+		),
+	)
+})
+
+// AdminLayout extends the base layout with an extra script that all admin pages need
+var AdminLayout = BaseLayout.Extend(
+	ScriptsBlock.Append(Script(Text("console.log('admin');"))),
+)
+
+func index(w http.ResponseWriter, r *http.Request) {
+	// the home page only uses the default content
+	_, _ = BaseLayout.Page()(w)
+}
+
+func about(w http.ResponseWriter, r *http.Request) {
+	_, _ = BaseLayout.Page(
+		TitleBlock.Define(Title("About")),
+		ContentBlock.Define(
+			H1(Text("About")),
+			P(Text("This page overrides the title and the content blocks")),
+		),
+	)(w)
+}
+
+func admin(w http.ResponseWriter, r *http.Request) {
+	_, _ = AdminLayout.Page(
+		ContentBlock.Define(H1(Text("Admin"))),
+		// appended after the script added by the admin layout
+		ScriptsBlock.Append(Script(Text("console.log('page');"))),
+	)(w)
+}
+
+func main() {
+	http.HandleFunc("/", index)
+	http.HandleFunc("/about", about)
+	http.HandleFunc("/admin", admin)
+	err := http.ListenAndServe(":8080", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
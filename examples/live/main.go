@@ -0,0 +1,80 @@
+package main
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"github.com/westcoastcode-se/gohtml/live"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Counter is a view that is rendered on the server. Clicking on the buttons sends an event to the server, which
+// updates the state and sends the changes back to the browser. The session serializes all events and updates,
+// so the view doesn't need a lock of its own
+type Counter struct {
+	count int
+	now   time.Time
+}
+
+func (c *Counter) Render() h.Node {
+	return h.Div(
+		h.P(h.Textf("Server time: %s", c.now.Format(time.TimeOnly))),
+		h.P(h.Textf("Count: %d", c.count)),
+		h.Button(a.Attrib("live-click", "inc"), h.Text("+1")),
+		h.Button(a.Attrib("live-click", "dec"), h.Text("-1")),
+	)
+}
+
+func (c *Counter) HandleEvent(s *live.Session, e live.Event) {
+	switch e.Name {
+	case "inc":
+		c.count++
+	case "dec":
+		c.count--
+	}
+}
+
+// Mount starts a goroutine that pushes the server time to the browser every second
+func (c *Counter) Mount(s *live.Session) {
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.Context().Done():
+				return
+			case t := <-ticker.C:
+				_ = s.Update(func() {
+					c.now = t
+				})
+			}
+		}
+	}()
+}
+
+var counter = live.New("/live/counter", func(r *http.Request) live.View {
+	return &Counter{now: time.Now()}
+})
+
+func index(w http.ResponseWriter, r *http.Request) {
+	_, _ = h.Html(a.Lang("en"),
+		h.Head(
+			h.Meta(a.Charset("UTF-8")),
+			h.Title("Example: Live"),
+		),
+		h.Body(
+			h.H1(h.Text("Example: Live")),
+			counter.Node(r),
+		),
+	)(h.WithContext(r.Context(), w))
+}
+
+func main() {
+	http.Handle("/live/counter", counter)
+	http.HandleFunc("/", index)
+	err := http.ListenAndServe(":8080", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
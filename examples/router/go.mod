@@ -0,0 +1,7 @@
+module router
+
+go 1.22
+
+replace github.com/westcoastcode-se/gohtml => ../../
+
+require github.com/westcoastcode-se/gohtml v0.0.5
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	. "github.com/westcoastcode-se/gohtml/h"
+	"github.com/westcoastcode-se/gohtml/router"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Routes in the application. Pages link to these variables instead of hard-coded paths, which means that a link
+// with a missing or wrongly typed path parameter results in a compile error
+var (
+	Home      = router.New("GET /{$}")
+	Product   = router.New1[int]("GET /products/{id}")
+	Review    = router.New2[int, string]("GET /products/{id}/reviews/{author}")
+	AddToCart = router.New1[int]("POST /products/{id}/cart")
+)
+
+type ProductItem struct {
+	ID   int
+	Name string
+}
+
+var products = []ProductItem{
+	{ID: 1, Name: "Coffee"},
+	{ID: 2, Name: "Tea"},
+}
+
+func page(c ...Node) RootNode {
+	return Html(a.Lang("en"),
+		Head(
+			Meta(a.Charset("UTF-8")),
+			Title("Example: Typed routing"),
+		),
+		Body(
+			Nav(A(Home.Href(), Text("Home"))),
+			Main(c...),
+		),
+	)
+}
+
+func home(w http.ResponseWriter, r *http.Request) {
+	_, _ = page(
+		H1(Text("Products")),
+		Ul(
+			EmitArray(products, func(p ProductItem) Node {
+				return Li(A(Product.Href(p.ID), Text(p.Name)))
+			}),
+		),
+	)(w)
+}
+
+func product(w http.ResponseWriter, r *http.Request, id int) {
+	if id < 1 || id > len(products) {
+		http.NotFound(w, r)
+		return
+	}
+	p := products[id-1]
+	_, _ = page(
+		H1(Text(p.Name)),
+		P(A(Review.Href(p.ID, "jane doe"), Text("Review by Jane Doe"))),
+		Form(a.Method("post"), AddToCart.Action(p.ID),
+			Button(a.Type("submit"), Text("Add to cart")),
+		),
+	)(w)
+}
+
+func review(w http.ResponseWriter, r *http.Request, id int, author string) {
+	_, _ = page(
+		H1(Text("Review")),
+		P(
+			Text("A review by "+html.EscapeString(author)+" of "),
+			A(Product.Href(id), Text("product #"+strconv.Itoa(id))),
+		),
+	)(w)
+}
+
+func addToCart(w http.ResponseWriter, r *http.Request, id int) {
+	http.Redirect(w, r, Product.URL(id).Query("added", "1").String(), http.StatusSeeOther)
+}
+
+func main() {
+	mux := http.NewServeMux()
+	Home.Handle(mux, home)
+	Product.Handle(mux, product)
+	Review.Handle(mux, review)
+	AddToCart.Handle(mux, addToCart)
+	err := http.ListenAndServe(":8080", mux)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,92 @@
+package forms
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// decode sets the fields of the supplied pointer to a struct using the supplied values
+func decode(values url.Values, dst any, s *State) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("form"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		if _, ok := values[name]; !ok {
+			continue
+		}
+		// fields with validation errors are left untouched
+		if len(s.Errors[name]) > 0 {
+			continue
+		}
+		if !set(rv.Field(i), values.Get(name)) {
+			s.AddError(name, "Invalid value")
+		}
+	}
+}
+
+// set converts the value into the type of the field. Returns false if the value can't be converted
+func set(v reflect.Value, value string) bool {
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		if value == "" {
+			return true
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return false
+		}
+		v.Set(reflect.ValueOf(t))
+		return true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		v.SetBool(value != "" && value != "false" && value != "0" && value != "off")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value == "" {
+			return true
+		}
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value == "" {
+			return true
+		}
+		i, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		if value == "" {
+			return true
+		}
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetFloat(f)
+	default:
+		return false
+	}
+	return true
+}
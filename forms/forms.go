@@ -0,0 +1,292 @@
+package forms
+
+import (
+	"errors"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FieldType is the type of input field
+type FieldType string
+
+const (
+	TypeText     FieldType = "text"
+	TypePassword FieldType = "password"
+	TypeEmail    FieldType = "email"
+	TypeNumber   FieldType = "number"
+	TypeDate     FieldType = "date"
+	TypeHidden   FieldType = "hidden"
+	TypeCheckbox FieldType = "checkbox"
+	TypeTextArea FieldType = "textarea"
+	TypeSelect   FieldType = "select"
+)
+
+// Option is a single option in a select field
+type Option struct {
+	Value string
+	Label string
+}
+
+// Field is a single field in a form
+type Field struct {
+	// Name of the field. It's also used to bind the value to a struct field with a matching form tag
+	Name string
+	// Label shown next to the field
+	Label string
+	// Type of field. TypeText is used if empty
+	Type FieldType
+	// Placeholder shown when the field is empty
+	Placeholder string
+	// Options for select fields
+	Options []Option
+	// Validators run when the form is parsed
+	Validators []Validator
+	// Attrs are added to the input element
+	Attrs []h.Node
+}
+
+func (f Field) fieldType() FieldType {
+	if f.Type == "" {
+		return TypeText
+	}
+	return f.Type
+}
+
+// validate returns the error messages for the supplied value
+func (f Field) validate(value string) []string {
+	var messages []string
+	if f.fieldType() == TypeNumber && value != "" {
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			messages = append(messages, "Must be a number")
+		}
+	}
+	if f.fieldType() == TypeSelect && value != "" && len(f.Options) > 0 {
+		found := false
+		for _, o := range f.Options {
+			found = found || o.Value == value
+		}
+		if !found {
+			messages = append(messages, "Must be one of the available options")
+		}
+	}
+	for _, v := range f.Validators {
+		if err := v.Validate(value); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	return messages
+}
+
+// State contains the values and the validation errors of a submitted form
+type State struct {
+	Values url.Values
+	// Errors contains the error messages for each field
+	Errors map[string][]string
+}
+
+// Valid returns true if no field has any errors
+func (s State) Valid() bool {
+	return len(s.Errors) == 0
+}
+
+// AddError adds an error message to the supplied field. This is useful for errors found after the form has
+// been validated, for example when a username is already taken
+func (s *State) AddError(field string, message string) {
+	if s.Errors == nil {
+		s.Errors = make(map[string][]string)
+	}
+	s.Errors[field] = append(s.Errors[field], message)
+}
+
+// Form describes a form and its fields. The same form is used to render the form, parse the submitted values and
+// render the form again with the submitted values and the validation errors
+//
+// Example:
+//
+//	var login = forms.Form{
+//	  ID:     "login",
+//	  Method: http.MethodPost,
+//	  Action: "/login",
+//	  Fields: []forms.Field{
+//	    {Name: "username", Label: "Username", Validators: []forms.Validator{forms.Required()}},
+//	    {Name: "password", Label: "Password", Type: forms.TypePassword, Validators: []forms.Validator{forms.Required()}},
+//	  },
+//	  SubmitText: "Login",
+//	}
+//
+//	func handleLogin(w http.ResponseWriter, r *http.Request) {
+//	  var req LoginRequest
+//	  state, err := login.Bind(r, &req)
+//	  if err != nil {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return
+//	  }
+//	  if !state.Valid() {
+//	    _, _ = Page(login.Node(state))(w)
+//	    return
+//	  }
+//	  ...
+//	}
+type Form struct {
+	// ID of the form. It's used as a prefix for the id of each field
+	ID     string
+	Method string
+	Action string
+	Fields []Field
+	// SubmitText is the text on the submit button. No button is rendered if empty
+	SubmitText string
+}
+
+// Parse parses and validates the values submitted by the supplied request. An error is returned if the body of the
+// request can't be parsed, for example if it's malformed or too large
+func (f *Form) Parse(r *http.Request) (State, error) {
+	s := State{
+		Values: url.Values{},
+	}
+	// ParseMultipartForm hides errors from ParseForm if the body isn't a multipart form
+	if err := r.ParseForm(); err != nil {
+		return s, err
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return s, err
+	}
+	for _, field := range f.Fields {
+		value := strings.TrimSpace(r.Form.Get(field.Name))
+		if field.fieldType() == TypePassword || field.fieldType() == TypeTextArea {
+			value = r.Form.Get(field.Name)
+		}
+		if _, ok := r.Form[field.Name]; ok {
+			s.Values.Set(field.Name, value)
+		}
+		for _, m := range field.validate(value) {
+			s.AddError(field.Name, m)
+		}
+	}
+	return s, nil
+}
+
+// Bind parses and validates the submitted values and decodes them into the supplied pointer to a struct.
+// Struct fields are matched using the form tag, or the name of the struct field if the tag is missing.
+// Values that can't be converted into the type of the struct field are added as errors. An error is returned if the
+// body of the request can't be parsed
+func (f *Form) Bind(r *http.Request, dst any) (State, error) {
+	s, err := f.Parse(r)
+	if err != nil {
+		return s, err
+	}
+	decode(s.Values, dst, &s)
+	return s, nil
+}
+
+// id returns the id of the supplied field
+func (f *Form) id(field Field) string {
+	if f.ID == "" {
+		return field.Name
+	}
+	return f.ID + "-" + field.Name
+}
+
+// Render renders the form without any values. This makes Form a h.Component
+func (f *Form) Render() h.Node {
+	return f.Node(State{})
+}
+
+// Node renders the form with the values and errors in the supplied state
+func (f *Form) Node(s State) h.Node {
+	fields := make([]h.Node, 0, len(f.Fields)+1)
+	for _, field := range f.Fields {
+		fields = append(fields, f.FieldNode(field, s))
+	}
+	if f.SubmitText != "" {
+		fields = append(fields, h.Button(
+			a.Type("submit"),
+			h.Text(html.EscapeString(f.SubmitText)),
+		))
+	}
+	return h.Form(
+		h.NodeIf(f.ID != "", a.ID(f.ID)),
+		h.NodeIf(f.Method != "", a.Method(f.Method)),
+		h.NodeIf(f.Action != "", a.Action(f.Action)),
+		h.Join(fields...),
+	)
+}
+
+// FieldNode renders a single field, its label and its error messages
+func (f *Form) FieldNode(field Field, s State) h.Node {
+	id := f.id(field)
+	errorID := id + "-error"
+	messages := s.Errors[field.Name]
+	value := s.Values.Get(field.Name)
+
+	attrs := []h.Node{
+		a.ID(id),
+		a.Name(field.Name),
+	}
+	for _, v := range field.Validators {
+		if v.attr != "" {
+			attrs = append(attrs, a.Attrib(v.attr, html.EscapeString(v.attrValue)))
+		}
+	}
+	if field.Placeholder != "" {
+		attrs = append(attrs, a.Attrib("placeholder", html.EscapeString(field.Placeholder)))
+	}
+	if len(messages) > 0 {
+		attrs = append(attrs,
+			a.Attrib("aria-invalid", "true"),
+			a.Attrib("aria-describedby", errorID),
+		)
+	}
+	attrs = append(attrs, field.Attrs...)
+
+	var input h.Node
+	switch t := field.fieldType(); t {
+	case TypeTextArea:
+		input = h.Textarea(append(attrs, h.Text(html.EscapeString(value)))...)
+	case TypeSelect:
+		options := make([]h.Node, len(field.Options))
+		for i, o := range field.Options {
+			options[i] = h.Option(
+				a.Value(html.EscapeString(o.Value)),
+				h.NodeIf(o.Value == value, a.Attrib("selected", "")),
+				h.Text(html.EscapeString(o.Label)),
+			)
+		}
+		input = h.Select(append(attrs, options...)...)
+	case TypeCheckbox:
+		attrs = append(attrs, a.Type(string(t)))
+		if value != "" && value != "false" {
+			attrs = append(attrs, a.Attrib("checked", ""))
+		}
+		input = h.Input(attrs...)
+	default:
+		attrs = append(attrs, a.Type(string(t)))
+		if t != TypePassword {
+			attrs = append(attrs, a.Value(html.EscapeString(value)))
+		}
+		input = h.Input(attrs...)
+	}
+	if field.fieldType() == TypeHidden {
+		return input
+	}
+
+	return h.Div(
+		a.Classes("field").If(len(messages) > 0, "field-invalid").Node(),
+		h.NodeIf(field.Label != "", h.Label(
+			a.For(id),
+			h.Text(html.EscapeString(field.Label)),
+		)),
+		input,
+		h.NodeIf(len(messages) > 0, h.Div(
+			a.ID(errorID),
+			a.Class("field-error"),
+			h.EmitArray(messages, func(m string) h.Node {
+				return h.P(h.Text(html.EscapeString(m)))
+			}),
+		)),
+	)
+}
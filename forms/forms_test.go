@@ -0,0 +1,133 @@
+package forms
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signup struct {
+	Name     string    `form:"name"`
+	Age      int       `form:"age"`
+	Birthday time.Time `form:"birthday"`
+	Terms    bool      `form:"terms"`
+	Ignored  string    `form:"-"`
+}
+
+var signupForm = Form{
+	ID: "signup",
+	Fields: []Field{
+		{Name: "name", Validators: []Validator{Required(), MaxLength(5)}},
+		{Name: "age", Type: TypeNumber, Validators: []Validator{Min(18)}},
+		{Name: "birthday", Type: TypeDate},
+		{Name: "terms", Type: TypeCheckbox},
+		{Name: "Ignored"},
+	},
+}
+
+func post(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestBind(t *testing.T) {
+	var dst signup
+	s, err := signupForm.Bind(post(url.Values{
+		"name":     {" Jane "},
+		"age":      {"30"},
+		"birthday": {"2000-01-02"},
+		"terms":    {"on"},
+		"Ignored":  {"x"},
+	}), &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Valid() {
+		t.Fatalf("expected the form to be valid but was %v", s.Errors)
+	}
+	expected := signup{Name: "Jane", Age: 30, Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), Terms: true}
+	if !reflect.DeepEqual(dst, expected) {
+		t.Errorf("expected %+v but was %+v", expected, dst)
+	}
+}
+
+func TestBindWithErrors(t *testing.T) {
+	dst := signup{Name: "unchanged"}
+	s, err := signupForm.Bind(post(url.Values{
+		"name":     {"Too long"},
+		"age":      {"x"},
+		"birthday": {"2000-13-01"},
+	}), &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]string{
+		"name":     {"Must be at most 5 characters"},
+		"age":      {"Must be a number", "Must be at least 18"},
+		"birthday": {"Invalid value"},
+	}
+	if !reflect.DeepEqual(s.Errors, expected) {
+		t.Errorf("expected %v but was %v", expected, s.Errors)
+	}
+	if dst.Name != "unchanged" {
+		t.Errorf("fields with errors must not be changed but was %q", dst.Name)
+	}
+	if s.Values.Get("name") != "Too long" {
+		t.Errorf("expected the submitted value to be kept but was %q", s.Values.Get("name"))
+	}
+}
+
+func TestBindMultipart(t *testing.T) {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	_ = mw.WriteField("name", "Jane")
+	_ = mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var dst signup
+	s, err := signupForm.Bind(r, &dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Valid() || dst.Name != "Jane" {
+		t.Errorf("expected the multipart form to be bound but was %+v with errors %v", dst, s.Errors)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	malformed := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("--x\r\nbroken"))
+	malformed.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	if _, err := signupForm.Parse(malformed); err == nil {
+		t.Error("expected an error for a malformed multipart body")
+	}
+
+	tooLarge := post(url.Values{"name": {"Jane"}})
+	tooLarge.Body = http.MaxBytesReader(httptest.NewRecorder(), tooLarge.Body, 4)
+	if _, err := signupForm.Parse(tooLarge); err == nil {
+		t.Error("expected an error for a body that is too large")
+	}
+}
+
+func TestNode(t *testing.T) {
+	s := State{
+		Values: url.Values{"name": {`"><script>`}},
+		Errors: map[string][]string{"name": {"This field is required"}},
+	}
+	sb := &strings.Builder{}
+	signupForm.FieldNode(signupForm.Fields[0], s)(0, sb)
+	html := sb.String()
+	for _, expected := range []string{`id="signup-name"`, `value="&#34;&gt;&lt;script&gt;"`, `required`, `maxlength="5"`,
+		"This field is required"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %q in %q", expected, html)
+		}
+	}
+}
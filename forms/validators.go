@@ -0,0 +1,140 @@
+package forms
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Validator validates the value of a single field. Some validators also emit the equivalent HTML validation
+// attribute, such as required or maxlength, so that the browser can validate the field before it's submitted
+type Validator struct {
+	attr      string
+	attrValue string
+	message   string
+	check     func(value string) error
+	// always is true if the validator should run even if the value is empty
+	always bool
+}
+
+// WithMessage returns a copy of the validator with a custom error message
+func (v Validator) WithMessage(message string) Validator {
+	v.message = message
+	return v
+}
+
+// Validate returns an error if the value isn't valid. Empty values are only validated by Required
+func (v Validator) Validate(value string) error {
+	if value == "" && !v.always {
+		return nil
+	}
+	if err := v.check(value); err != nil {
+		if v.message != "" {
+			return errors.New(v.message)
+		}
+		return err
+	}
+	return nil
+}
+
+// errInvalid is returned by the built-in validators. The message of the validator is shown instead
+var errInvalid = errors.New("invalid value")
+
+// test converts a test into a check function
+func test(f func(value string) bool) func(value string) error {
+	return func(value string) error {
+		if f(value) {
+			return nil
+		}
+		return errInvalid
+	}
+}
+
+// Required makes sure that the field has a value
+func Required() Validator {
+	return Validator{
+		attr:    "required",
+		message: "This field is required",
+		check:   test(func(value string) bool { return value != "" }),
+		always:  true,
+	}
+}
+
+// MinLength makes sure that the value has at least n characters
+func MinLength(n int) Validator {
+	return Validator{
+		attr:      "minlength",
+		attrValue: strconv.Itoa(n),
+		message:   fmt.Sprintf("Must be at least %d characters", n),
+		check:     test(func(value string) bool { return utf8.RuneCountInString(value) >= n }),
+	}
+}
+
+// MaxLength makes sure that the value has at most n characters
+func MaxLength(n int) Validator {
+	return Validator{
+		attr:      "maxlength",
+		attrValue: strconv.Itoa(n),
+		message:   fmt.Sprintf("Must be at most %d characters", n),
+		check:     test(func(value string) bool { return utf8.RuneCountInString(value) <= n }),
+	}
+}
+
+// Pattern makes sure that the whole value matches the supplied regular expression. The expression must be
+// compatible with both Go and JavaScript since it's also emitted as a pattern attribute
+func Pattern(expr string, message string) Validator {
+	re := regexp.MustCompile("^(?:" + expr + ")$")
+	return Validator{
+		attr:      "pattern",
+		attrValue: expr,
+		message:   message,
+		check:     test(re.MatchString),
+	}
+}
+
+// Email makes sure that the value is an email address
+func Email() Validator {
+	return Validator{
+		message: "Must be a valid email address",
+		check: test(func(value string) bool {
+			addr, err := mail.ParseAddress(value)
+			return err == nil && addr.Address == value
+		}),
+	}
+}
+
+// Min makes sure that the value is a number larger than or equal to min
+func Min(min float64) Validator {
+	return Validator{
+		attr:      "min",
+		attrValue: strconv.FormatFloat(min, 'f', -1, 64),
+		message:   fmt.Sprintf("Must be at least %s", strconv.FormatFloat(min, 'f', -1, 64)),
+		check: test(func(value string) bool {
+			f, err := strconv.ParseFloat(value, 64)
+			return err == nil && f >= min
+		}),
+	}
+}
+
+// Max makes sure that the value is a number less than or equal to max
+func Max(max float64) Validator {
+	return Validator{
+		attr:      "max",
+		attrValue: strconv.FormatFloat(max, 'f', -1, 64),
+		message:   fmt.Sprintf("Must be at most %s", strconv.FormatFloat(max, 'f', -1, 64)),
+		check: test(func(value string) bool {
+			f, err := strconv.ParseFloat(value, 64)
+			return err == nil && f <= max
+		}),
+	}
+}
+
+// Func creates a validator from a function. The message of the returned error is shown to the user
+func Func(f func(value string) error) Validator {
+	return Validator{
+		check: f,
+	}
+}
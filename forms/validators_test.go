@@ -0,0 +1,46 @@
+package forms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator Validator
+		value     string
+		expected  string
+	}{
+		{"required", Required(), "x", ""},
+		{"required empty", Required(), "", "This field is required"},
+		{"min length", MinLength(3), "åäö", ""},
+		{"min length too short", MinLength(3), "ab", "Must be at least 3 characters"},
+		{"min length empty", MinLength(3), "", ""},
+		{"max length", MaxLength(2), "åä", ""},
+		{"max length too long", MaxLength(2), "abc", "Must be at most 2 characters"},
+		{"pattern", Pattern("[a-z]+", "Lower case only"), "abc", ""},
+		{"pattern partial match", Pattern("[a-z]+", "Lower case only"), "abc1", "Lower case only"},
+		{"email", Email(), "a@example.com", ""},
+		{"email with name", Email(), "A <a@example.com>", "Must be a valid email address"},
+		{"email invalid", Email(), "a@", "Must be a valid email address"},
+		{"min", Min(1.5), "1.5", ""},
+		{"min too small", Min(1.5), "1", "Must be at least 1.5"},
+		{"min not a number", Min(1), "x", "Must be at least 1"},
+		{"max", Max(10), "10", ""},
+		{"max too large", Max(10), "11", "Must be at most 10"},
+		{"func", Func(func(string) error { return errors.New("taken") }), "x", "taken"},
+		{"custom message", Required().WithMessage("Enter a name"), "", "Enter a name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := ""
+			if err := test.validator.Validate(test.value); err != nil {
+				actual = err.Error()
+			}
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrDuplicateAttribute is the error returned when rendering a tag with the same attribute more than once
+// and the AttributesError policy is used
+var ErrDuplicateAttribute = errors.New("duplicate attribute")
+
+// AttributePolicy decides what happens when the same attribute is added more than once to a single tag
+type AttributePolicy int
+
+const (
+	// AttributesAllow writes all attributes as they are. This is the default policy and the fastest one, since
+	// attributes are written directly to the writer
+	AttributesAllow AttributePolicy = iota
+	// AttributesLastWins only writes the last value of an attribute. The attribute is written at the position
+	// where it was first added
+	AttributesLastWins
+	// AttributesMerge merges the values of the class and style attributes. Other attributes work like AttributesLastWins
+	AttributesMerge
+	// AttributesError keeps the first value of an attribute and makes the rendering fail with ErrDuplicateAttribute.
+	// The error is returned by the RootNode, such as Html or Fragment, that renders the tag. A Node called directly
+	// with a writer has no way of returning an error, which means that the duplicate is dropped without an error
+	AttributesError
+)
+
+// attributePolicyKey is the context key for the AttributePolicy
+type attributePolicyKey struct{}
+
+// WithAttributePolicy returns a context that makes all tags rendered with it use the supplied policy
+//
+// Example:
+//
+//	ctx := WithAttributePolicy(r.Context(), AttributesMerge)
+//	_, _ = Html(
+//	  Body(
+//	    Div(a.Class("a"), a.Class("b")),
+//	  ),
+//	)(WithContext(ctx, w))
+func WithAttributePolicy(ctx context.Context, p AttributePolicy) context.Context {
+	return context.WithValue(ctx, attributePolicyKey{}, p)
+}
+
+// attributePolicyOf returns the attribute policy used when rendering into the supplied writer
+func attributePolicyOf(w io.Writer) AttributePolicy {
+	switch t := w.(type) {
+	case *errorAwareWriter:
+		return t.policy
+	case *attributeCollector:
+		return t.policy
+	case *staticProbe:
+		return AttributesAllow
+	}
+	p, _ := ContextOf(w).Value(attributePolicyKey{}).(AttributePolicy)
+	return p
+}
+
+// AttributeWriter is implemented by writers that want to process attributes before they are written. Nodes that
+// emit attributes should use this interface, if available, instead of writing the attribute directly
+type AttributeWriter interface {
+	WriteAttribute(key, value string)
+}
+
+// writeAttribute writes a single attribute
+func writeAttribute(w io.Writer, key, value string) {
+	_, _ = w.Write([]byte{' '})
+	_, _ = w.Write([]byte(key))
+	_, _ = w.Write([]byte("=\""))
+	_, _ = w.Write([]byte(value))
+	_, _ = w.Write([]byte{'"'})
+}
+
+// Attrib emits a single attribute
+func Attrib(key string, value string) Node {
+	return func(b byte, w io.Writer) byte {
+		if aw, ok := w.(AttributeWriter); ok {
+			aw.WriteAttribute(key, value)
+			return b
+		}
+		writeAttribute(w, key, value)
+		return b
+	}
+}
+
+// fail makes the rendering fail with the supplied error
+func fail(w io.Writer, err error) {
+	for {
+		switch t := w.(type) {
+		case *errorAwareWriter:
+			if t.err == nil {
+				t.err = err
+			}
+			return
+		case *writerWithContext:
+			w = t.Writer
+		case *attributeCollector:
+			w = t.w
+		case *separatedWriter:
+			w = t.w
+		default:
+			return
+		}
+	}
+}
+
+// Fail is a node that makes the rendering fail with the supplied error. It's used by nodes that can't be rendered
+// correctly, for example if a required value is missing or if an asset can't be read. The node is never compiled
+// ahead of time by Compile, since the error would be lost. The error is returned by the RootNode, such as Html or
+// Fragment, that renders the node. It's dropped if the node is called directly with a writer
+func Fail(err error) Node {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
+		if err != nil {
+			fail(w, err)
+		}
+		return b
+	}
+}
+
+type attribute struct {
+	key   string
+	value string
+}
+
+// attributeCollector collects all attributes on a tag so that the attribute policy can be applied. The
+// attributes are written as soon as the tag gets its first child
+type attributeCollector struct {
+	w       io.Writer
+	policy  AttributePolicy
+	attribs []attribute
+	flushed bool
+	name    string
+	hooks   []TagHook
+	void    bool
+	// quote is the quote character of an attribute value written directly to the collector, if inside one
+	quote byte
+	// content is emitted by the hooks at the start of the tag
+	content []Node
+	// observers are called with the content of the tag when all child-nodes have been written
+	observers []func(content []byte)
+	// written is the content of the tag written so far, if there are any observers
+	written []byte
+	// probe is set if the tag is written to a static probe. Tags with duplicate attributes are rendered differently
+	// depending on the attribute policy, which means that they aren't static
+	probe *staticProbe
+}
+
+func (c *attributeCollector) WriteAttribute(key, value string) {
+	if c.flushed {
+		writeAttribute(c.w, key, value)
+		return
+	}
+	for i := range c.attribs {
+		if c.attribs[i].key != key {
+			continue
+		}
+		switch {
+		case c.probe != nil:
+			c.probe.dynamic = true
+		case c.policy == AttributesError:
+			fail(c.w, fmt.Errorf("%w: %s", ErrDuplicateAttribute, key))
+		case c.policy == AttributesMerge && key == "class":
+			c.attribs[i].value = mergeClasses(c.attribs[i].value, value)
+		case c.policy == AttributesMerge && key == "style":
+			c.attribs[i].value = mergeStyles(c.attribs[i].value, value)
+		default:
+			c.attribs[i].value = value
+		}
+		return
+	}
+	c.attribs = append(c.attribs, attribute{key: key, value: value})
+}
+
+func (c *attributeCollector) Write(p []byte) (int, error) {
+	if c.flushed {
+		if c.observers != nil {
+			c.written = append(c.written, p...)
+		}
+		return c.w.Write(p)
+	}
+	// nodes, such as Raw, might write attributes directly. Everything up until the end of the start tag
+	// is therefore written as it is and the collected attributes are written right before the '>'
+	end := c.endOfStartTag(p)
+	if end == -1 {
+		return c.w.Write(p)
+	}
+	n, err := c.w.Write(p[:end])
+	if err != nil {
+		return n, err
+	}
+	c.flush()
+	m, err := c.w.Write(p[end:])
+	if c.observers != nil {
+		// the content starts after the '>'
+		if i := bytes.IndexByte(p[end:], '>'); i != -1 {
+			c.written = append(c.written, p[end+i+1:]...)
+		}
+	}
+	c.writeContent()
+	return n + m, err
+}
+
+// endOfStartTag returns the index of the '>' that ends the start tag, or -1 if the bytes only contain attributes.
+// A '>' inside a quoted attribute value is ignored
+func (c *attributeCollector) endOfStartTag(p []byte) int {
+	for i, ch := range p {
+		switch {
+		case c.quote != 0:
+			if ch == c.quote {
+				c.quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			c.quote = ch
+		case ch == '>':
+			if i > 0 && p[i-1] == '/' {
+				return i - 1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// writeContent writes the content added by the hooks
+func (c *attributeCollector) writeContent() {
+	var b byte
+	for _, n := range c.content {
+		b = n(b, c)
+	}
+	c.content = nil
+}
+
+// close calls the observers with the content of the tag
+func (c *attributeCollector) close() {
+	for _, o := range c.observers {
+		o(c.written)
+	}
+}
+
+func (c *attributeCollector) Context() context.Context {
+	return ContextOf(c.w)
+}
+
+func (c *attributeCollector) flush() {
+	if c.flushed {
+		return
+	}
+	c.flushed = true
+	if len(c.hooks) > 0 {
+		t := &OpenTag{Name: c.name, collector: c}
+		for _, hook := range c.hooks {
+			if n := hook(t); n != nil && !c.void {
+				c.content = append(c.content, n)
+			}
+		}
+	}
+	for _, a := range c.attribs {
+		writeAttribute(c.w, a.key, a.value)
+	}
+}
+
+func mergeClasses(existing, value string) string {
+	classes := strings.Fields(existing)
+	for _, v := range strings.Fields(value) {
+		found := false
+		for _, c := range classes {
+			if c == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			classes = append(classes, v)
+		}
+	}
+	return strings.Join(classes, " ")
+}
+
+func mergeStyles(existing, value string) string {
+	existing = strings.TrimRight(strings.TrimSpace(existing), ";")
+	value = strings.TrimSpace(value)
+	if existing == "" {
+		return value
+	}
+	if value == "" {
+		return existing
+	}
+	return existing + ";" + value
+}
+
+// children emits the child-nodes of a tag. The attribute policy and the tag hooks are applied on all attributes added
+// before the first child-node. Void tags can't get any content from the hooks
+func children(name string, void bool, b byte, w io.Writer, c []Node) byte {
+	if isHookedTag(name) {
+		// the tag might be changed by a hook, so it can't be pre-rendered
+		skipDynamic(w)
+	}
+	policy := attributePolicyOf(w)
+	hooks := tagHooksOf(w, name)
+	probe := probeOf(w)
+	if policy == AttributesAllow && len(hooks) == 0 && probe == nil {
+		for _, cc := range c {
+			b = cc(b, w)
+		}
+		return b
+	}
+
+	ac := &attributeCollector{
+		w:      w,
+		policy: policy,
+		name:   name,
+		hooks:  hooks,
+		void:   void,
+		probe:  probe,
+	}
+	i := 0
+	for ; i < len(c) && !ac.flushed; i++ {
+		b = c[i](b, ac)
+	}
+	if !ac.flushed {
+		ac.flush()
+		// content from the hooks is written when the first child is written. Write it now since there are no children
+		if ac.content != nil && b != 0 {
+			_, _ = w.Write([]byte{b})
+			ac.writeContent()
+			b = 0
+		}
+	}
+	// the content has to pass through the collector if a hook wants to see it
+	out := w
+	if ac.observers != nil {
+		out = ac
+	}
+	for ; i < len(c); i++ {
+		b = c[i](b, out)
+	}
+	ac.close()
+	return b
+}
+
+// unwrapCollector returns the writer behind a collector if all attributes have been written. This prevents
+// a chain of collectors from being built when tags are nested. Collectors that observe the content of their tag
+// are kept, since the content of nested tags is part of it
+func unwrapCollector(w io.Writer) io.Writer {
+	if ac, ok := w.(*attributeCollector); ok && ac.flushed && ac.observers == nil {
+		return ac.w
+	}
+	return w
+}
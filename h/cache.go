@@ -2,6 +2,7 @@ package h
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"sync"
 	"time"
@@ -90,27 +91,76 @@ func (cs *InMemoryCacheStorage) isValid(key any) bool {
 const CacheForever = 1000000 * time.Hour
 
 // Cache rendering of all children in the supplied duration. This is normally useful for when
-// you have a lot of data fetched from IO
+// you have a lot of data fetched from IO.
+//
+// The children are rendered with the context of the writer, but the result is reused for all later renders with the
+// same key. The result is therefore not cached if any of the children depend on the render, that is if a tag hook
+// is applied (for example a CSP nonce or a CSRF token) or if content is registered with UseHead. The key must
+// include everything else the children read from the context, such as the locale
 func Cache(cacheStorage CacheStorage, key any, evictDuration time.Duration, c ...Node) Node {
 	if item, found := cacheStorage.Get(key); found {
 		return func(b byte, w io.Writer) byte {
+			if skipDynamic(w) {
+				return b
+			}
 			_, _ = w.Write(item.bytes)
 			return item.token
 		}
 	}
 
 	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
 		bb := bytes.Buffer{}
-		ww := errorAwareWriter{w: &bb}
+		ctx, probe := probeContext(ContextOf(w))
+		ww := newErrorAwareWriter(&bb, ctx)
 		for _, cc := range c {
-			b = cc(b, &ww)
+			b = cc(b, ww)
+		}
+		if ww.err != nil {
+			fail(w, ww.err)
+			return b
 		}
 		// if no error happened then actually write the result on the supplied writer
-		if ww.err == nil {
-			result := bb.Bytes()
-			_, _ = w.Write(result[:])
+		result := bb.Bytes()
+		_, _ = w.Write(result[:])
+		if hc := HeadCollectorOf(w); hc != nil {
+			for _, e := range probe.head.entries {
+				hc.Add(e.key, e.node)
+			}
+		}
+		if !probe.dynamic && len(probe.head.entries) == 0 {
 			cacheStorage.Set(key, result[:], b, evictDuration)
 		}
 		return b
 	}
 }
+
+// cacheProbe keeps track of if the rendered content depends on the render, in which case it can't be cached
+type cacheProbe struct {
+	dynamic bool
+	head    *HeadCollector
+}
+
+// probeContext returns a context where all tag hooks, and all content registered with UseHead, are recorded
+// by the returned probe
+func probeContext(ctx context.Context) (context.Context, *cacheProbe) {
+	probe := &cacheProbe{head: newHeadCollector()}
+	if _, ok := ctx.Value(headCollectorKey{}).(*HeadCollector); ok {
+		ctx = context.WithValue(ctx, headCollectorKey{}, probe.head)
+	}
+	if hooks, ok := ctx.Value(tagHooksKey{}).(map[string][]TagHook); ok {
+		probed := make(map[string][]TagHook, len(hooks))
+		for name, h := range hooks {
+			for _, hook := range h {
+				probed[name] = append(probed[name], func(t *OpenTag) Node {
+					probe.dynamic = true
+					return hook(t)
+				})
+			}
+		}
+		ctx = context.WithValue(ctx, tagHooksKey{}, probed)
+	}
+	return ctx, probe
+}
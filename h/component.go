@@ -0,0 +1,136 @@
+package h
+
+import (
+	"io"
+	"reflect"
+)
+
+// Component is the contract shared by all reusable components. A component is normally a struct containing
+// the properties of the component and the Render method converts those properties into a Node
+type Component interface {
+	// Render creates the node tree for this component
+	Render() Node
+}
+
+// ComponentFunc makes it possible to use an ordinary function as a Component
+type ComponentFunc func() Node
+
+// Render calls f()
+func (f ComponentFunc) Render() Node {
+	return f()
+}
+
+// isNil returns true if the component is nil or if it's a nil pointer, map, slice or function wrapped in the interface
+func isNil(c Component) bool {
+	if c == nil {
+		return true
+	}
+	switch v := reflect.ValueOf(c); v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Render converts one or more components into a single node, so that components can be used wherever a Node is accepted.
+// Each component is rendered when the node is processed. Nil components, including nil pointers, are ignored
+//
+// Example:
+//
+//	Body(
+//	  Render(Card{Title: "Hello"}),
+//	)
+func Render(c ...Component) Node {
+	return func(b byte, w io.Writer) byte {
+		for _, cc := range c {
+			if isNil(cc) {
+				continue
+			}
+			b = cc.Render()(b, w)
+		}
+		return b
+	}
+}
+
+// RenderArray renders an array of components. Nil components, including nil pointers, are ignored
+func RenderArray[T Component](arr []T) Node {
+	return func(b byte, w io.Writer) byte {
+		for _, item := range arr {
+			if isNil(item) {
+				continue
+			}
+			b = item.Render()(b, w)
+		}
+		return b
+	}
+}
+
+// Slots is meant to be embedded in components that accept child-nodes from the caller. Children is the default slot and
+// Named contains content for named slots, such as "header" or "footer"
+//
+// Example:
+//
+//	type Card struct {
+//	  h.Slots
+//	  Title string
+//	}
+//
+//	func (c Card) Render() h.Node {
+//	  return h.Div(a.Class("card"),
+//	    h.Header(c.Slot("header", h.H2(h.Text(c.Title)))),
+//	    h.Div(a.Class("card-body"), c.Default()),
+//	    c.SlotIf("footer", h.Footer),
+//	  )
+//	}
+type Slots struct {
+	// Children is the content of the default slot
+	Children []Node
+	// Named contains the content of each named slot
+	Named map[string][]Node
+}
+
+// Fill returns a copy of the slots where the named slot is set to the supplied nodes
+func (s Slots) Fill(name string, c ...Node) Slots {
+	named := make(map[string][]Node, len(s.Named)+1)
+	for k, v := range s.Named {
+		named[k] = v
+	}
+	named[name] = c
+	s.Named = named
+	return s
+}
+
+// With returns a copy of the slots where the supplied nodes are added to the default slot
+func (s Slots) With(c ...Node) Slots {
+	s.Children = append(s.Children[:len(s.Children):len(s.Children)], c...)
+	return s
+}
+
+// Has returns true if the named slot has been filled by the caller
+func (s Slots) Has(name string) bool {
+	return len(s.Named[name]) > 0
+}
+
+// Slot emits the content of the named slot. The fallback nodes are emitted if the slot is empty
+func (s Slots) Slot(name string, fallback ...Node) Node {
+	if c := s.Named[name]; len(c) > 0 {
+		return Join(c...)
+	}
+	return Join(fallback...)
+}
+
+// SlotIf wraps the content of the named slot in the supplied tag function, but only if the slot has been filled
+func (s Slots) SlotIf(name string, tag func(c ...Node) Node) Node {
+	if !s.Has(name) {
+		return Empty()
+	}
+	return tag(s.Named[name]...)
+}
+
+// Default emits the children of the default slot. The fallback nodes are emitted if there are no children
+func (s Slots) Default(fallback ...Node) Node {
+	if len(s.Children) > 0 {
+		return Join(s.Children...)
+	}
+	return Join(fallback...)
+}
@@ -0,0 +1,38 @@
+package h
+
+import (
+	"strings"
+	"testing"
+)
+
+type card struct {
+	title string
+}
+
+func (c *card) Render() Node {
+	return P(Text(c.title))
+}
+
+func TestRenderIgnoresNilComponents(t *testing.T) {
+	var nilCard *card
+	var nilFunc ComponentFunc
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{"nil", Render(nil, &card{title: "a"}), "<p>a</p>"},
+		{"nil pointer", Render(nilCard, &card{title: "a"}), "<p>a</p>"},
+		{"nil func", Render(nilFunc), ""},
+		{"array", RenderArray([]*card{{title: "a"}, nil, {title: "b"}}), "<p>a</p><p>b</p>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			test.node(0, sb)
+			if sb.String() != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, sb.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package h
+
+import (
+	"context"
+	"io"
+)
+
+// contextWriter is implemented by all writers that carry a context.Context while a node tree is being rendered
+type contextWriter interface {
+	Context() context.Context
+}
+
+// writerWithContext is an io.Writer that makes a context available to all nodes written to it
+type writerWithContext struct {
+	io.Writer
+	ctx context.Context
+}
+
+func (w *writerWithContext) Context() context.Context {
+	return w.ctx
+}
+
+// WithContext returns a writer that makes the supplied context available to all nodes written to it. This is normally
+// used when rendering a response in a http handler, so that nodes can read values put into the request context by middlewares
+//
+// Example:
+//
+//	func index(w http.ResponseWriter, r *http.Request) {
+//	  _, _ = Html(
+//	    Body(Text("Hello World")),
+//	  )(WithContext(r.Context(), w))
+//	}
+func WithContext(ctx context.Context, w io.Writer) io.Writer {
+	return &writerWithContext{
+		Writer: w,
+		ctx:    ctx,
+	}
+}
+
+// ContextOf returns the context associated with the supplied writer. Returns context.Background() if the writer
+// doesn't have a context
+func ContextOf(w io.Writer) context.Context {
+	if cw, ok := w.(contextWriter); ok {
+		if ctx := cw.Context(); ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// FromContext calls the supplied function with the context of the writer when the node is processed and then
+// emits the returned node
+func FromContext(f func(ctx context.Context) Node) Node {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
+		return f(ContextOf(w))(b, w)
+	}
+}
+
+// WithValue emits the child-nodes with a context containing the supplied key-value pair
+func WithValue(key, value any, c ...Node) Node {
+	return func(b byte, w io.Writer) byte {
+		// the context itself doesn't matter when probing for static content
+		if probeOf(w) == nil {
+			w = WithContext(context.WithValue(ContextOf(w), key, value), w)
+		}
+		for _, cc := range c {
+			b = cc(b, w)
+		}
+		return b
+	}
+}
@@ -21,6 +21,9 @@ import (
 //	})
 func EmitChannel(f func() chan Node) Node {
 	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
 		ch := f()
 		for n := range ch {
 			b = n(b, w)
@@ -47,6 +50,9 @@ func EmitChannelEx(f func() chan Node, props ChannelProps) Node {
 
 	timeout := time.After(props.Timeout)
 	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
 		// call the supplied function so that we can get a channel to consume
 		ch := f()
 	rangeLoop:
@@ -88,6 +94,10 @@ func EmitArray[T any](arr []T, emit func(t T) Node) Node {
 // EmitMap emits a map of key-value pairs and converts them into nodes to be written
 func EmitMap[K comparable, V any](arr map[K]V, emit func(key K, value V) Node) Node {
 	return func(b byte, w io.Writer) byte {
+		// the iteration order of a map is not stable
+		if skipDynamic(w) {
+			return b
+		}
 		for key, value := range arr {
 			b = emit(key, value)(b, w)
 		}
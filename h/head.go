@@ -0,0 +1,170 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// headCollectorKey is the context key for the HeadCollector used while rendering a document
+type headCollectorKey struct{}
+
+// headEntry is a single unique node registered in a HeadCollector
+type headEntry struct {
+	key     string
+	node    Node
+	emitted bool
+}
+
+// HeadCollector collects stylesheets, scripts and other content that nodes anywhere in the tree want to put in the
+// head of the document. Each entry is identified by a key, which is used to remove duplicates.
+//
+// Since the document is streamed, any content registered before the end of the head tag is emitted in the head
+// and anything registered after that is emitted at the end of the body tag. Use CollectHead if you want all content
+// to end up in the head tag.
+type HeadCollector struct {
+	keys    map[string]struct{}
+	entries []*headEntry
+	// buf is set when all content should be put in the head tag (see CollectHead)
+	buf *bytes.Buffer
+	// at is the offset in buf where the head tag ends
+	at int
+}
+
+func newHeadCollector() *HeadCollector {
+	return &HeadCollector{
+		keys: make(map[string]struct{}),
+		at:   -1,
+	}
+}
+
+// HeadCollectorOf returns the HeadCollector used when rendering into the supplied writer. Returns nil if the
+// node isn't rendered as part of a document
+func HeadCollectorOf(w io.Writer) *HeadCollector {
+	hc, _ := ContextOf(w).Value(headCollectorKey{}).(*HeadCollector)
+	return hc
+}
+
+// Add registers the supplied node unless a node with the same key is already registered. Returns false if
+// the key already exists
+func (hc *HeadCollector) Add(key string, n Node) bool {
+	if _, ok := hc.keys[key]; ok {
+		return false
+	}
+	hc.keys[key] = struct{}{}
+	hc.entries = append(hc.entries, &headEntry{key: key, node: n})
+	return true
+}
+
+// emit writes all entries that are not yet written
+func (hc *HeadCollector) emit(b byte, w io.Writer) byte {
+	for _, e := range hc.entries {
+		if e.emitted {
+			continue
+		}
+		e.emitted = true
+		b = e.node(b, w)
+	}
+	return b
+}
+
+// emitHeadContent is added at the end of the head tag
+func emitHeadContent(b byte, w io.Writer) byte {
+	if skipDynamic(w) {
+		return b
+	}
+	hc := HeadCollectorOf(w)
+	if hc == nil {
+		return b
+	}
+	if hc.buf != nil {
+		if hc.at == -1 {
+			// the end of the start tag might still be pending if the head tag doesn't have any other children
+			if b != 0 {
+				_, _ = w.Write([]byte{b})
+				b = 0
+			}
+			hc.at = hc.buf.Len()
+		}
+		return b
+	}
+	return hc.emit(b, w)
+}
+
+// emitBodyContent is added at the end of the body tag
+func emitBodyContent(b byte, w io.Writer) byte {
+	if skipDynamic(w) {
+		return b
+	}
+	hc := HeadCollectorOf(w)
+	if hc == nil || hc.buf != nil {
+		return b
+	}
+	return hc.emit(b, w)
+}
+
+// UseHead registers a node to be emitted in the head of the document. Nodes with the same key are only emitted once.
+// The node is emitted where UseHead is put if the node isn't rendered as part of a document created by Html
+func UseHead(key string, n Node) Node {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
+		hc := HeadCollectorOf(w)
+		if hc == nil {
+			return n(b, w)
+		}
+		hc.Add(key, n)
+		return b
+	}
+}
+
+// UseStylesheet registers a stylesheet link to be emitted in the head of the document. Stylesheets are identified by
+// the href, which means that the same stylesheet is only linked once
+//
+// Example:
+//
+//	func DatePicker() Node {
+//	  return Join(
+//	    UseStylesheet("/static/datepicker.css"),
+//	    UseScript("/static/datepicker.js"),
+//	    Input(a.Type("date")),
+//	  )
+//	}
+func UseStylesheet(href string, c ...Node) Node {
+	return UseHead("link:"+href, Link(append([]Node{Attrib("rel", "stylesheet"), Attrib("href", href)}, c...)...))
+}
+
+// UseScript registers a script to be emitted in the head of the document. Scripts are identified by the src, which
+// means that the same script is only loaded once
+func UseScript(src string, c ...Node) Node {
+	return UseHead("script:"+src, Script(append([]Node{Attrib("src", src)}, c...)...))
+}
+
+// CollectHead renders the entire document before it's written to the supplied writer. This makes it possible for
+// all content registered by UseHead, UseStylesheet and UseScript to be put in the head of the document,
+// no matter where in the tree they are used. The downside is that nothing is written until the whole document is done.
+// The content is put at the end of the document if it doesn't have a head tag
+func CollectHead(root RootNode) RootNode {
+	return func(w io.Writer) (int, error) {
+		hc := newHeadCollector()
+		hc.buf = &bytes.Buffer{}
+		ctx := context.WithValue(ContextOf(w), headCollectorKey{}, hc)
+		if _, err := root(WithContext(ctx, hc.buf)); err != nil {
+			return 0, err
+		}
+		result := hc.buf.Bytes()
+		at := hc.at
+		if at == -1 {
+			at = len(result)
+		}
+
+		// the collected content is rendered with the same hooks and attribute policy as the rest of the document
+		we := newErrorAwareWriter(w, ctx)
+		_, _ = we.Write(result[:at])
+		hc.buf = nil
+		hc.emit(0, we)
+		_, _ = we.Write(result[at:])
+		return we.len, we.err
+	}
+}
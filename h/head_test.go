@@ -0,0 +1,103 @@
+package h
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// nonceHook sets a nonce on all tags it's registered for
+func nonceHook(t *OpenTag) Node {
+	t.SetAttr("nonce", "abc")
+	return nil
+}
+
+func TestCollectHeadAppliesTagHooks(t *testing.T) {
+	ctx := WithTagHook(context.Background(), nonceHook, "script", "style")
+	sb := &strings.Builder{}
+	_, err := CollectHead(Html(
+		Head(Title("x")),
+		Body(
+			UseScript("/x.js"),
+			UseHead("style:x", Style(Text("p{}"))),
+		),
+	))(WithContext(ctx, sb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<!DOCTYPE html><html><head><title>x</title><script src="/x.js" nonce="abc"></script>` +
+		`<style nonce="abc">p{}</style></head><body></body></html>`
+	if sb.String() != expected {
+		t.Errorf("expected %q but was %q", expected, sb.String())
+	}
+}
+
+func TestCollectHeadWithEmptyHead(t *testing.T) {
+	sb := &strings.Builder{}
+	_, err := CollectHead(Html(
+		Head(),
+		Body(UseStylesheet("/x.css")),
+	))(sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<!DOCTYPE html><html><head><link rel="stylesheet" href="/x.css"/></head><body></body></html>`
+	if sb.String() != expected {
+		t.Errorf("expected %q but was %q", expected, sb.String())
+	}
+}
+
+func TestHeadWithEmptyHead(t *testing.T) {
+	sb := &strings.Builder{}
+	_, err := Html(
+		UseStylesheet("/x.css"),
+		Head(),
+		Body(),
+	)(sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<!DOCTYPE html><html><head><link rel="stylesheet" href="/x.css"/></head><body></body></html>`
+	if sb.String() != expected {
+		t.Errorf("expected %q but was %q", expected, sb.String())
+	}
+}
+
+func TestCacheRegistersHeadContent(t *testing.T) {
+	storage := CreateInMemoryCacheStorage()
+	page := func() RootNode {
+		return CollectHead(Html(
+			Head(),
+			Body(Cache(storage, "key", CacheForever, UseStylesheet("/x.css"), P(Text("x")))),
+		))
+	}
+	expected := `<!DOCTYPE html><html><head><link rel="stylesheet" href="/x.css"/></head><body><p>x</p></body></html>`
+	for i := 0; i < 2; i++ {
+		sb := &strings.Builder{}
+		if _, err := page()(sb); err != nil {
+			t.Fatal(err)
+		}
+		if sb.String() != expected {
+			t.Errorf("expected %q but was %q", expected, sb.String())
+		}
+	}
+	if _, found := storage.Get("key"); found {
+		t.Error("content that registers head content must not be cached")
+	}
+}
+
+func TestCacheDoesNotCacheHookedTags(t *testing.T) {
+	storage := CreateInMemoryCacheStorage()
+	for _, nonce := range []string{"a", "b"} {
+		ctx := WithTagHook(context.Background(), func(t *OpenTag) Node {
+			t.SetAttr("nonce", nonce)
+			return nil
+		}, "script")
+		sb := &strings.Builder{}
+		Cache(storage, "key", CacheForever, Script(Text("x()")))(0, WithContext(ctx, sb))
+		expected := `<script nonce="` + nonce + `">x()</script>`
+		if sb.String() != expected {
+			t.Errorf("expected %q but was %q", expected, sb.String())
+		}
+	}
+}
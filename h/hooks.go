@@ -0,0 +1,123 @@
+package h
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+// tagHooksKey is the context key for all registered tag hooks
+type tagHooksKey struct{}
+
+// OpenTag is a tag that is being rendered. It's supplied to the tag hooks right before the attributes are written
+type OpenTag struct {
+	// Name of the tag
+	Name      string
+	collector *attributeCollector
+}
+
+// Context returns the context of the render
+func (t *OpenTag) Context() context.Context {
+	return ContextOf(t.collector.w)
+}
+
+// Attr returns the value of an attribute and a boolean that indicates if the tag has the attribute
+func (t *OpenTag) Attr(key string) (string, bool) {
+	for _, a := range t.collector.attribs {
+		if a.key == key {
+			return a.value, true
+		}
+	}
+	return "", false
+}
+
+// HasAttrValue returns true if the tag has an attribute with the supplied value. The value is case-insensitive
+func (t *OpenTag) HasAttrValue(key string, value string) bool {
+	v, ok := t.Attr(key)
+	return ok && strings.EqualFold(v, value)
+}
+
+// SetAttr adds an attribute to the tag or replaces the value of an existing attribute
+func (t *OpenTag) SetAttr(key string, value string) {
+	for i := range t.collector.attribs {
+		if t.collector.attribs[i].key == key {
+			t.collector.attribs[i].value = value
+			return
+		}
+	}
+	t.collector.attribs = append(t.collector.attribs, attribute{key: key, value: value})
+}
+
+// OnContent registers a function that's called with the content of the tag when all of its child-nodes have been
+// written. The content is everything written between the start tag and the end tag, including the content emitted by
+// the hooks. The supplied slice must not be kept after the function returns
+func (t *OpenTag) OnContent(fn func(content []byte)) {
+	t.collector.observers = append(t.collector.observers, fn)
+}
+
+// TagHook is called every time a tag it's registered for is rendered. The hook can read and change the attributes of
+// the tag. The returned node, if not nil, is emitted as the first child of the tag
+type TagHook func(t *OpenTag) Node
+
+// WithTagHook returns a context where the supplied hook is called for all tags with the supplied names. This is how
+// middlewares can change tags rendered by the application, for example adding a nonce to all script tags
+//
+// Example:
+//
+//	ctx := WithTagHook(r.Context(), func(t *OpenTag) Node {
+//	  t.SetAttr("nonce", nonce)
+//	  return nil
+//	}, "script", "style")
+func WithTagHook(ctx context.Context, hook TagHook, tags ...string) context.Context {
+	HookedTags(tags...)
+	existing, _ := ctx.Value(tagHooksKey{}).(map[string][]TagHook)
+	hooks := make(map[string][]TagHook, len(existing)+len(tags))
+	for k, v := range existing {
+		hooks[k] = v
+	}
+	for _, t := range tags {
+		hooks[t] = append(hooks[t][:len(hooks[t]):len(hooks[t])], hook)
+	}
+	return context.WithValue(ctx, tagHooksKey{}, hooks)
+}
+
+var (
+	hookedTagsMutex sync.RWMutex
+	// hookedTags are the tags that tag hooks might change. The html, script, style and form tags are changed by the
+	// i18n, csp and csrf packages
+	hookedTags = map[string]bool{"html": true, "script": true, "style": true, "form": true}
+)
+
+// HookedTags marks the supplied tags as tags that tag hooks might change when they are rendered. These tags are never
+// pre-rendered by Compile, since the hooks would only be applied once. Tags are marked automatically by WithTagHook, but
+// that's normally done by a middleware when a request is handled, which is after the nodes have been compiled.
+// Call this when the application starts if you register hooks for other tags than html, script, style and form
+func HookedTags(tags ...string) {
+	hookedTagsMutex.Lock()
+	defer hookedTagsMutex.Unlock()
+	for _, t := range tags {
+		hookedTags[t] = true
+	}
+}
+
+// isHookedTag returns true if tag hooks might change the supplied tag
+func isHookedTag(name string) bool {
+	hookedTagsMutex.RLock()
+	defer hookedTagsMutex.RUnlock()
+	return hookedTags[name]
+}
+
+// tagHooksOf returns all hooks registered for the supplied tag
+func tagHooksOf(w io.Writer, name string) []TagHook {
+	switch t := w.(type) {
+	case *errorAwareWriter:
+		return t.hooks[name]
+	case *attributeCollector:
+		return tagHooksOf(t.w, name)
+	case *staticProbe:
+		return nil
+	}
+	hooks, _ := ContextOf(w).Value(tagHooksKey{}).(map[string][]TagHook)
+	return hooks[name]
+}
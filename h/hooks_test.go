@@ -0,0 +1,64 @@
+package h
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTagHookWithRawAttributes(t *testing.T) {
+	ctx := WithTagHook(context.Background(), func(t *OpenTag) Node {
+		t.SetAttr("id", "h")
+		return Text("hook")
+	}, "x-hook")
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{"raw attribute", Tag("x-hook", Raw(` data-x="a>b"`), Text("x")), `<x-hook data-x="a>b" id="h">hookx</x-hook>`},
+		{"static attribute", Tag("x-hook", Static(Attrib("class", "c")), Text("x")), `<x-hook class="c" id="h">hookx</x-hook>`},
+		{"no children", Tag("x-hook", Raw(` data-x='y'`)), `<x-hook data-x='y' id="h">hook</x-hook>`},
+		{"raw content", Tag("x-hook", Text(""), Raw("<p>x</p>")), `<x-hook id="h">hook<p>x</p></x-hook>`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			test.node(0, WithContext(ctx, sb))
+			if sb.String() != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestTagHookOnContent(t *testing.T) {
+	var contents []string
+	ctx := WithTagHook(context.Background(), func(t *OpenTag) Node {
+		t.OnContent(func(content []byte) {
+			contents = append(contents, string(content))
+		})
+		return Text("h;")
+	}, "x-hook")
+	tests := []struct {
+		name     string
+		node     Node
+		expected string
+	}{
+		{"text", Tag("x-hook", Text("a"), Text("b")), "h;ab"},
+		{"attributes", Tag("x-hook", Attrib("id", "x"), Raw(` data-x="a>b"`), Text("a")), "h;a"},
+		{"no children", Tag("x-hook"), "h;"},
+		{"nested tags", Tag("x-hook", P(Text("a")), Span(Text("b"))), "h;<p>a</p><span>b</span>"},
+		{"raw content", Tag("x-hook", Text(""), Raw("<p>x</p>")), "h;<p>x</p>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contents = nil
+			sb := &strings.Builder{}
+			test.node(0, WithContext(ctx, sb))
+			if len(contents) != 1 || contents[0] != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, contents)
+			}
+		})
+	}
+}
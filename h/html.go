@@ -1,6 +1,8 @@
 package h
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -20,15 +22,59 @@ func NodeIf(test bool, n Node) Node {
 	}
 }
 
-// Attribs gives you a way of creating an attribute with multiple values using the Value, ValueIf functions
+// separatedWriter writes a space between the content of each value in an attribute. Values that don't write
+// anything are ignored
+type separatedWriter struct {
+	w       io.Writer
+	written bool
+	first   bool
+}
+
+func (s *separatedWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if s.first {
+		s.first = false
+		if s.written {
+			_, _ = s.w.Write([]byte{' '})
+		}
+	}
+	s.written = true
+	return s.w.Write(p)
+}
+
+func (s *separatedWriter) Context() context.Context {
+	return ContextOf(s.w)
+}
+
+// Attribs gives you a way of creating an attribute with multiple values using the Value, ValueIf functions.
+// Each value is separated by a space
 func Attribs(key string, values ...Node) Node {
 	return func(b byte, w io.Writer) byte {
+		if probeOf(w) != nil {
+			for _, a := range values {
+				b = a(b, w)
+			}
+			return b
+		}
+		if aw, ok := w.(AttributeWriter); ok {
+			bb := bytes.Buffer{}
+			sw := &separatedWriter{w: &bb}
+			for _, a := range values {
+				sw.first = true
+				b = a(b, sw)
+			}
+			aw.WriteAttribute(key, bb.String())
+			return b
+		}
 		_, _ = w.Write([]byte{' '})
 		_, _ = w.Write([]byte(key))
 		_, _ = w.Write([]byte("=\""))
+		sw := &separatedWriter{w: w}
 		for _, a := range values {
-			_, _ = w.Write([]byte{' '})
-			b = a(b, w)
+			sw.first = true
+			b = a(b, sw)
 		}
 		_, _ = w.Write([]byte{'"'})
 		return b
@@ -58,12 +104,10 @@ func TagEmpty(name string, c ...Node) Node {
 		if b != 0 {
 			_, _ = w.Write([]byte{b})
 		}
+		w = unwrapCollector(w)
 		_, _ = w.Write([]byte{'<'})
 		_, _ = w.Write([]byte(name))
-		b = '>'
-		for _, cc := range c {
-			b = cc(b, w)
-		}
+		_ = children(name, true, '>', w, c)
 		_, _ = w.Write([]byte("/>"))
 		return 0
 	}
@@ -75,12 +119,10 @@ func Tag(name string, c ...Node) Node {
 		if b != 0 {
 			_, _ = w.Write([]byte{b})
 		}
+		w = unwrapCollector(w)
 		_, _ = w.Write([]byte{'<'})
 		_, _ = w.Write([]byte(name))
-		b = '>'
-		for _, cc := range c {
-			b = cc(b, w)
-		}
+		b = children(name, false, '>', w, c)
 		if b != 0 {
 			_, _ = w.Write([]byte{b})
 		}
@@ -181,7 +223,10 @@ func TextfIf(format string, test bool, a ...any) Node {
 
 // Log is logging a message when this node is being processed
 func Log(v ...interface{}) Node {
-	return func(b byte, _ io.Writer) byte {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
 		log.Print(v...)
 		return b
 	}
@@ -189,7 +234,10 @@ func Log(v ...interface{}) Node {
 
 // Logf is logging a message when this node is being processed
 func Logf(format string, a ...interface{}) Node {
-	return func(b byte, _ io.Writer) byte {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
 		log.Printf(format, a...)
 		return b
 	}
@@ -258,8 +306,10 @@ func Blockquote(c ...Node) Node {
 	return Tag("blockquote", c...)
 }
 
+// Body creates the body tag. Any content registered using UseHead after the head tag has been written is emitted
+// at the end of the body
 func Body(c ...Node) Node {
-	return Tag("body", c...)
+	return Tag("body", append(c[:len(c):len(c)], emitBodyContent)...)
 }
 
 func Br(c ...Node) Node {
@@ -386,8 +436,9 @@ func H6(c ...Node) Node {
 	return Tag("h6", c...)
 }
 
+// Head creates the head tag. Any content registered using UseHead is emitted at the end of the head
 func Head(c ...Node) Node {
-	return Tag("head", c...)
+	return Tag("head", append(c[:len(c):len(c)], emitHeadContent)...)
 }
 
 func Header(c ...Node) Node {
@@ -404,16 +455,15 @@ func Hr(c ...Node) Node {
 
 func Html(c ...Node) RootNode {
 	return func(w io.Writer) (int, error) {
-		we := &errorAwareWriter{
-			w: w,
+		ctx := ContextOf(w)
+		if _, ok := ctx.Value(headCollectorKey{}).(*HeadCollector); !ok {
+			ctx = context.WithValue(ctx, headCollectorKey{}, newHeadCollector())
 		}
+		we := newErrorAwareWriter(w, ctx)
 		if _, err := we.Write([]byte("<!DOCTYPE html><html")); err != nil {
 			return 0, err
 		}
-		b := byte('>')
-		for _, cc := range c {
-			b = cc(b, we)
-		}
+		b := children("html", false, '>', we, c)
 		if b != 0 {
 			_, _ = we.Write([]byte{b})
 		}
@@ -422,6 +472,18 @@ func Html(c ...Node) RootNode {
 	}
 }
 
+// Fragment creates a root node that renders the supplied nodes without a surrounding html document. This is
+// useful when responding to a request that replaces a part of an already loaded page
+func Fragment(c ...Node) RootNode {
+	return func(w io.Writer) (int, error) {
+		we := newErrorAwareWriter(w, ContextOf(w))
+		for _, cc := range c {
+			_ = cc(0, we)
+		}
+		return we.len, we.err
+	}
+}
+
 func I(c ...Node) Node {
 	return Tag("i", c...)
 }
@@ -0,0 +1,143 @@
+package h
+
+// Block represents a named placeholder in a Layout. Blocks are declared once as variables and then referenced by both
+// the layout and the pages, which means that a misspelled block results in a compile error
+type Block struct {
+	name     string
+	defaults []Node
+}
+
+// NewBlock declares a new block. The default nodes are emitted if no page, or extended layout, defines the block
+func NewBlock(name string, defaults ...Node) *Block {
+	return &Block{
+		name:     name,
+		defaults: defaults,
+	}
+}
+
+// Name returns the name of the block
+func (b *Block) Name() string {
+	return b.name
+}
+
+// Define replaces the content of the block
+func (b *Block) Define(c ...Node) BlockDefinition {
+	return BlockDefinition{
+		block:   b,
+		content: c,
+	}
+}
+
+// Append adds the supplied nodes after the content defined by the layout being extended, or after the
+// default content if the block hasn't been defined yet
+func (b *Block) Append(c ...Node) BlockDefinition {
+	return BlockDefinition{
+		block:   b,
+		content: c,
+		appends: true,
+	}
+}
+
+// BlockDefinition is the content of a block as defined by a page or an extended layout
+type BlockDefinition struct {
+	block   *Block
+	content []Node
+	appends bool
+}
+
+// Blocks contains the resolved content for all blocks in a page
+type Blocks struct {
+	content map[*Block][]Node
+}
+
+// Block emits the content of the supplied block
+func (bl Blocks) Block(b *Block) Node {
+	if c, ok := bl.content[b]; ok {
+		return Join(c...)
+	}
+	return Join(b.defaults...)
+}
+
+// Has returns true if the block has been defined by the page or by an extended layout
+func (bl Blocks) Has(b *Block) bool {
+	_, ok := bl.content[b]
+	return ok
+}
+
+// define applies the supplied definitions on top of the blocks
+func (bl Blocks) define(defs []BlockDefinition) {
+	for _, d := range defs {
+		if !d.appends {
+			bl.content[d.block] = d.content
+			continue
+		}
+		existing, ok := bl.content[d.block]
+		if !ok {
+			existing = d.block.defaults
+		}
+		c := make([]Node, 0, len(existing)+len(d.content))
+		c = append(c, existing...)
+		bl.content[d.block] = append(c, d.content...)
+	}
+}
+
+// Layout is a base page in which pages can override the content of named blocks, much like block and define
+// in html/template
+//
+// Example:
+//
+//	var (
+//	  TitleBlock   = h.NewBlock("title", h.Title("My Site"))
+//	  ContentBlock = h.NewBlock("content")
+//	)
+//
+//	var Base = h.NewLayout(func(b h.Blocks) h.RootNode {
+//	  return h.Html(
+//	    h.Head(b.Block(TitleBlock)),
+//	    h.Body(b.Block(ContentBlock)),
+//	  )
+//	})
+//
+//	func about(w http.ResponseWriter, r *http.Request) {
+//	  _, _ = Base.Page(
+//	    ContentBlock.Define(h.H1(h.Text("About"))),
+//	  )(w)
+//	}
+type Layout struct {
+	parent *Layout
+	defs   []BlockDefinition
+	render func(b Blocks) RootNode
+}
+
+// NewLayout creates a new base layout
+func NewLayout(render func(b Blocks) RootNode) *Layout {
+	return &Layout{
+		render: render,
+	}
+}
+
+// Extend creates a new layout based on this layout where the supplied blocks are defined. Pages
+// using the new layout can then override those blocks again
+func (l *Layout) Extend(defs ...BlockDefinition) *Layout {
+	return &Layout{
+		parent: l,
+		defs:   defs,
+		render: l.render,
+	}
+}
+
+// Page creates a root node where the supplied blocks are defined
+func (l *Layout) Page(defs ...BlockDefinition) RootNode {
+	blocks := Blocks{
+		content: make(map[*Block][]Node),
+	}
+	var chain []*Layout
+	for ll := l; ll != nil; ll = ll.parent {
+		chain = append(chain, ll)
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		blocks.define(chain[i].defs)
+	}
+	blocks.define(defs)
+	return l.render(blocks)
+}
@@ -0,0 +1,144 @@
+package h
+
+import (
+	"bytes"
+	"io"
+)
+
+// staticProbe is the writer used when testing if a node tree is static or not. Nodes that depend on
+// things outside the tree itself are expected to mark the probe as dynamic and skip their work
+type staticProbe struct {
+	bytes.Buffer
+	dynamic bool
+}
+
+// probeOf returns the static probe that the supplied writer writes to, or nil if the nodes aren't being probed
+func probeOf(w io.Writer) *staticProbe {
+	switch t := w.(type) {
+	case *staticProbe:
+		return t
+	case *attributeCollector:
+		return t.probe
+	}
+	return nil
+}
+
+// skipDynamic marks the supplied writer as dynamic if it's a static probe. Returns true if the calling
+// node should skip whatever work it was planning to do, since the result will never be used anyway
+func skipDynamic(w io.Writer) bool {
+	if p := probeOf(w); p != nil {
+		p.dynamic = true
+		return true
+	}
+	return false
+}
+
+// Dynamic marks the supplied nodes as dynamic, which prevents them from being compiled ahead of time by Compile.
+// Use this for your own nodes that read state when they are rendered, for example the time or a global variable
+func Dynamic(c ...Node) Node {
+	return func(b byte, w io.Writer) byte {
+		if skipDynamic(w) {
+			return b
+		}
+		for _, cc := range c {
+			b = cc(b, w)
+		}
+		return b
+	}
+}
+
+// IsStatic returns true if the supplied nodes always render the same content. Nodes created by the
+// functions in the h and a packages are static unless they emit content from a channel, a map, a cache or
+// a logger. Tags that tag hooks might change, such as script, style and form (see HookedTags), are never static.
+// Neither are tags with the same attribute more than once, since the attribute policy decides how they are rendered.
+// Custom nodes are assumed to be static unless they are wrapped by Dynamic
+func IsStatic(c ...Node) bool {
+	p := &staticProbe{}
+	for _, cc := range c {
+		_ = cc(0, p)
+		if p.dynamic {
+			return false
+		}
+	}
+	return true
+}
+
+// compiled contains the pre-rendered content of a static node tree
+type compiled struct {
+	// content when rendered without a pending token
+	plain []byte
+	// content when rendered with the '>' token pending
+	open []byte
+	// index in plain where the pending token is written
+	at int
+	// token returned after the content is written
+	token byte
+	// consumed is false if the tree never writes the pending token, i.e. it only contains attributes
+	consumed bool
+}
+
+// Static renders the supplied nodes once and emits the result as a single write each time the returned
+// node is processed. The caller is responsible for making sure that the nodes really are static.
+// The nodes are rendered without a render context, which means that no tag hooks and no attribute policy
+// are applied to them. A script tag won't get a CSP nonce and a form won't get a CSRF token, for example.
+// Use Compile if you want the framework to figure that out for you
+//
+// Example:
+//
+//	var footer = Static(
+//	  Footer(
+//	    P(Text("Copyright")),
+//	  ),
+//	)
+func Static(c ...Node) Node {
+	plain := bytes.Buffer{}
+	t0 := Join(c...)(0, &plain)
+	open := bytes.Buffer{}
+	_ = Join(c...)('>', &open)
+
+	cc := compiled{
+		plain: plain.Bytes(),
+		open:  open.Bytes(),
+		at:    len(plain.Bytes()),
+		token: t0,
+	}
+	if len(cc.open) > len(cc.plain) {
+		cc.consumed = true
+		for i := range cc.plain {
+			if cc.plain[i] != cc.open[i] {
+				cc.at = i
+				break
+			}
+		}
+	}
+
+	return func(b byte, w io.Writer) byte {
+		if !cc.consumed {
+			_, _ = w.Write(cc.plain)
+			if b != 0 {
+				return b
+			}
+			return cc.token
+		}
+		switch b {
+		case 0:
+			_, _ = w.Write(cc.plain)
+		case '>':
+			_, _ = w.Write(cc.open)
+		default:
+			_, _ = w.Write(cc.plain[:cc.at])
+			_, _ = w.Write([]byte{b})
+			_, _ = w.Write(cc.plain[cc.at:])
+		}
+		return cc.token
+	}
+}
+
+// Compile pre-renders the supplied nodes using Static if all of them are static. If not then
+// the nodes are emitted as usual every time they are processed
+func Compile(c ...Node) Node {
+	if IsStatic(c...) {
+		return Static(c...)
+	}
+	return Join(c...)
+}
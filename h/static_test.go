@@ -0,0 +1,93 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIsStatic(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     Node
+		expected bool
+	}{
+		{"text", Div(Text("x")), true},
+		{"dynamic", Div(Dynamic(Text("x"))), false},
+		{"script", Div(Script(Text("x()"))), false},
+		{"style", Style(Text("p{}")), false},
+		{"form", Form(Input()), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := IsStatic(test.node); actual != test.expected {
+				t.Errorf("expected %v but was %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompileAppliesTagHooks(t *testing.T) {
+	node := Compile(Script(Text("x()")))
+	for _, nonce := range []string{"a", "b"} {
+		ctx := WithTagHook(context.Background(), func(t *OpenTag) Node {
+			t.SetAttr("nonce", nonce)
+			return nil
+		}, "script")
+		sb := &strings.Builder{}
+		node(0, WithContext(ctx, sb))
+		expected := `<script nonce="` + nonce + `">x()</script>`
+		if sb.String() != expected {
+			t.Errorf("expected %q but was %q", expected, sb.String())
+		}
+	}
+}
+
+func TestHookedTags(t *testing.T) {
+	HookedTags("x-hooked")
+	if IsStatic(Tag("x-hooked")) {
+		t.Error("expected a hooked tag to be dynamic")
+	}
+}
+
+func TestCompileAppliesAttributePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   AttributePolicy
+		expected string
+		err      error
+	}{
+		{"allow", AttributesAllow, `<div class="a" class="b">x</div>`, nil},
+		{"last wins", AttributesLastWins, `<div class="b">x</div>`, nil},
+		{"merge", AttributesMerge, `<div class="a b">x</div>`, nil},
+		{"error", AttributesError, "", ErrDuplicateAttribute},
+	}
+	tree := func() Node {
+		return Div(Attrib("class", "a"), Attrib("class", "b"), Text("x"))
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithAttributePolicy(context.Background(), test.policy)
+			for _, node := range []Node{tree(), Compile(tree())} {
+				sb := &strings.Builder{}
+				_, err := Fragment(node)(WithContext(ctx, sb))
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected error %v but was %v", test.err, err)
+				}
+				if test.err == nil && sb.String() != test.expected {
+					t.Errorf("expected %q but was %q", test.expected, sb.String())
+				}
+			}
+		})
+	}
+}
+
+func TestIsStaticWithDuplicateAttributes(t *testing.T) {
+	if IsStatic(Div(Attrib("class", "a"), Attrib("class", "b"))) {
+		t.Error("a tag with duplicate attributes must not be static")
+	}
+	if !IsStatic(Div(Attrib("class", "a"), Attrib("id", "b"), P(Attrib("class", "c")))) {
+		t.Error("a tag with unique attributes must be static")
+	}
+}
@@ -1,13 +1,31 @@
 package h
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // errorAwareWriter gives us a way of keeping track of all memory being written to the writer and
 // a simple way of centralizing the handling of any error that might occur while writing the HTML content
 type errorAwareWriter struct {
-	w   io.Writer
-	len int
-	err error
+	w      io.Writer
+	ctx    context.Context
+	policy AttributePolicy
+	hooks  map[string][]TagHook
+	len    int
+	err    error
+}
+
+// newErrorAwareWriter creates a writer where the render options are read from the supplied context
+func newErrorAwareWriter(w io.Writer, ctx context.Context) *errorAwareWriter {
+	policy, _ := ctx.Value(attributePolicyKey{}).(AttributePolicy)
+	hooks, _ := ctx.Value(tagHooksKey{}).(map[string][]TagHook)
+	return &errorAwareWriter{
+		w:      w,
+		ctx:    ctx,
+		policy: policy,
+		hooks:  hooks,
+	}
 }
 
 func (e *errorAwareWriter) Write(b []byte) (int, error) {
@@ -19,3 +37,7 @@ func (e *errorAwareWriter) Write(b []byte) (int, error) {
 	e.len += i
 	return i, e.err
 }
+
+func (e *errorAwareWriter) Context() context.Context {
+	return e.ctx
+}
@@ -0,0 +1,350 @@
+package hx
+
+import (
+	"encoding/json"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// attr emits an htmx attribute where the value is escaped
+func attr(key, value string) h.Node {
+	return h.Attrib(key, html.EscapeString(value))
+}
+
+func Get(url string) h.Node {
+	return attr("hx-get", url)
+}
+
+func Post(url string) h.Node {
+	return attr("hx-post", url)
+}
+
+func Put(url string) h.Node {
+	return attr("hx-put", url)
+}
+
+func Patch(url string) h.Node {
+	return attr("hx-patch", url)
+}
+
+func Delete(url string) h.Node {
+	return attr("hx-delete", url)
+}
+
+// Target sets the element to swap. Use the Closest, Find, Next and Previous functions for relative targets
+func Target(selector string) h.Node {
+	return attr("hx-target", selector)
+}
+
+// TargetThis is the target that points to the element itself
+const TargetThis = "this"
+
+// Closest creates a target selector for the closest ancestor matching the selector
+func Closest(selector string) string {
+	return "closest " + selector
+}
+
+// Find creates a target selector for the first child matching the selector
+func Find(selector string) string {
+	return "find " + selector
+}
+
+// Next creates a target selector for the next sibling matching the selector
+func Next(selector string) string {
+	return "next " + selector
+}
+
+// Previous creates a target selector for the previous sibling matching the selector
+func Previous(selector string) string {
+	return "previous " + selector
+}
+
+// SwapStrategy decides how the response content is swapped into the target
+type SwapStrategy string
+
+const (
+	InnerHTML   SwapStrategy = "innerHTML"
+	OuterHTML   SwapStrategy = "outerHTML"
+	TextContent SwapStrategy = "textContent"
+	BeforeBegin SwapStrategy = "beforebegin"
+	AfterBegin  SwapStrategy = "afterbegin"
+	BeforeEnd   SwapStrategy = "beforeend"
+	AfterEnd    SwapStrategy = "afterend"
+	SwapDelete  SwapStrategy = "delete"
+	SwapNone    SwapStrategy = "none"
+)
+
+// SwapModifier changes how a swap is performed
+type SwapModifier string
+
+// duration formats a duration the way htmx expects it
+func duration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
+
+// SwapDelay waits the supplied duration before the content is swapped
+func SwapDelay(d time.Duration) SwapModifier {
+	return SwapModifier("swap:" + duration(d))
+}
+
+// Settle waits the supplied duration before the content is settled
+func Settle(d time.Duration) SwapModifier {
+	return SwapModifier("settle:" + duration(d))
+}
+
+// Scroll scrolls the target, or the supplied selector, to the "top" or "bottom"
+func Scroll(position string, selector ...string) SwapModifier {
+	if len(selector) > 0 {
+		return SwapModifier("scroll:" + selector[0] + ":" + position)
+	}
+	return SwapModifier("scroll:" + position)
+}
+
+// Show scrolls the target, or the supplied selector, into view at the "top" or "bottom"
+func Show(position string, selector ...string) SwapModifier {
+	if len(selector) > 0 {
+		return SwapModifier("show:" + selector[0] + ":" + position)
+	}
+	return SwapModifier("show:" + position)
+}
+
+// Transition decides if the view transitions API should be used
+func Transition(enabled bool) SwapModifier {
+	return SwapModifier("transition:" + strconv.FormatBool(enabled))
+}
+
+// FocusScroll decides if a focused element should be scrolled into view
+func FocusScroll(enabled bool) SwapModifier {
+	return SwapModifier("focus-scroll:" + strconv.FormatBool(enabled))
+}
+
+// swapValue combines a strategy with its modifiers
+func swapValue(s SwapStrategy, modifiers []SwapModifier) string {
+	sb := strings.Builder{}
+	sb.WriteString(string(s))
+	for _, m := range modifiers {
+		sb.WriteByte(' ')
+		sb.WriteString(string(m))
+	}
+	return sb.String()
+}
+
+// Swap decides how the response is swapped into the target
+//
+// Example:
+//
+//	hx.Swap(hx.OuterHTML, hx.Settle(time.Second))
+func Swap(s SwapStrategy, modifiers ...SwapModifier) h.Node {
+	return attr("hx-swap", swapValue(s, modifiers))
+}
+
+// SwapOOB marks an element in the response to be swapped out of band. Use "true" to replace the element with the same id
+func SwapOOB(value string) h.Node {
+	return attr("hx-swap-oob", value)
+}
+
+// TriggerSpec is a single event that triggers a request. Use Event or Every to create one
+type TriggerSpec struct {
+	event     string
+	modifiers []string
+}
+
+// Event creates a trigger for the supplied event, for example "click" or "keyup"
+func Event(name string) TriggerSpec {
+	return TriggerSpec{event: name}
+}
+
+// Every creates a trigger that polls with the supplied interval
+func Every(d time.Duration) TriggerSpec {
+	return TriggerSpec{event: "every " + duration(d)}
+}
+
+var (
+	// Load triggers when the element is loaded
+	Load = Event("load")
+	// Revealed triggers when the element is scrolled into the viewport
+	Revealed = Event("revealed")
+	// Intersect triggers when the element intersects with the viewport
+	Intersect = Event("intersect")
+)
+
+func (t TriggerSpec) with(modifier string) TriggerSpec {
+	t.modifiers = append(t.modifiers[:len(t.modifiers):len(t.modifiers)], modifier)
+	return t
+}
+
+// Filter only triggers if the supplied javascript expression is true
+func (t TriggerSpec) Filter(expr string) TriggerSpec {
+	t.event = t.event + "[" + expr + "]"
+	return t
+}
+
+// Once only triggers once
+func (t TriggerSpec) Once() TriggerSpec {
+	return t.with("once")
+}
+
+// Changed only triggers if the value of the element has changed
+func (t TriggerSpec) Changed() TriggerSpec {
+	return t.with("changed")
+}
+
+// Delay waits until no new event has been triggered for the supplied duration
+func (t TriggerSpec) Delay(d time.Duration) TriggerSpec {
+	return t.with("delay:" + duration(d))
+}
+
+// Throttle ignores new events for the supplied duration
+func (t TriggerSpec) Throttle(d time.Duration) TriggerSpec {
+	return t.with("throttle:" + duration(d))
+}
+
+// From listens to events on another element
+func (t TriggerSpec) From(selector string) TriggerSpec {
+	return t.with("from:" + selector)
+}
+
+// Target only triggers if the event target matches the selector
+func (t TriggerSpec) Target(selector string) TriggerSpec {
+	return t.with("target:" + selector)
+}
+
+// Consume prevents the event from triggering requests on parent elements
+func (t TriggerSpec) Consume() TriggerSpec {
+	return t.with("consume")
+}
+
+// Queue decides which events to queue while a request is in flight: "first", "last", "all" or "none"
+func (t TriggerSpec) Queue(q string) TriggerSpec {
+	return t.with("queue:" + q)
+}
+
+// String returns the trigger as it's written in the hx-trigger attribute
+func (t TriggerSpec) String() string {
+	if len(t.modifiers) == 0 {
+		return t.event
+	}
+	return t.event + " " + strings.Join(t.modifiers, " ")
+}
+
+// Trigger decides which events that trigger a request
+//
+// Example:
+//
+//	hx.Trigger(hx.Event("keyup").Changed().Delay(500*time.Millisecond), hx.Load)
+func Trigger(t ...TriggerSpec) h.Node {
+	values := make([]string, len(t))
+	for i, tt := range t {
+		values[i] = tt.String()
+	}
+	return attr("hx-trigger", strings.Join(values, ", "))
+}
+
+// Select picks a part of the response to swap
+func Select(selector string) h.Node {
+	return attr("hx-select", selector)
+}
+
+// SelectOOB picks parts of the response to swap out of band
+func SelectOOB(selectors ...string) h.Node {
+	return attr("hx-select-oob", strings.Join(selectors, ","))
+}
+
+// PushURL pushes the supplied url into the browser history. Use "true" to push the request url
+func PushURL(url string) h.Node {
+	return attr("hx-push-url", url)
+}
+
+// ReplaceURL replaces the current url in the browser history. Use "true" to use the request url
+func ReplaceURL(url string) h.Node {
+	return attr("hx-replace-url", url)
+}
+
+// Vals adds the supplied values to the request. The values are encoded as json
+func Vals(values map[string]any) h.Node {
+	return jsonAttr("hx-vals", values)
+}
+
+// Headers adds the supplied headers to the request. The headers are encoded as json
+func Headers(headers map[string]string) h.Node {
+	return jsonAttr("hx-headers", headers)
+}
+
+func jsonAttr(key string, v any) h.Node {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return h.Empty()
+	}
+	return attr(key, string(b))
+}
+
+// Confirm shows a confirm dialog before the request is made
+func Confirm(message string) h.Node {
+	return attr("hx-confirm", message)
+}
+
+// Prompt shows a prompt before the request is made. The result is sent in the HX-Prompt header
+func Prompt(message string) h.Node {
+	return attr("hx-prompt", message)
+}
+
+// Indicator is the element that gets the htmx-request class while the request is in flight
+func Indicator(selector string) h.Node {
+	return attr("hx-indicator", selector)
+}
+
+// Include adds the values of other elements to the request
+func Include(selector string) h.Node {
+	return attr("hx-include", selector)
+}
+
+// Params filters the parameters sent with the request: "*", "none", "not <list>" or a list of names
+func Params(value string) h.Node {
+	return attr("hx-params", value)
+}
+
+// Boost converts normal links and forms into ajax requests
+func Boost(enabled bool) h.Node {
+	return attr("hx-boost", strconv.FormatBool(enabled))
+}
+
+// Sync synchronizes requests between elements, for example "closest form:abort"
+func Sync(value string) h.Node {
+	return attr("hx-sync", value)
+}
+
+// DisabledElt adds the disabled attribute on the matching elements while the request is in flight
+func DisabledElt(selector string) h.Node {
+	return attr("hx-disabled-elt", selector)
+}
+
+// Encoding changes the encoding of the request, for example to "multipart/form-data"
+func Encoding(value string) h.Node {
+	return attr("hx-encoding", value)
+}
+
+// Ext enables the supplied extensions on the element
+func Ext(extensions ...string) h.Node {
+	return attr("hx-ext", strings.Join(extensions, ","))
+}
+
+// On handles an event using inline javascript, for example On("htmx:before-request", "alert('hi')")
+func On(event string, script string) h.Node {
+	return attr("hx-on:"+event, script)
+}
+
+// Preserve keeps the element unchanged between requests. The element must have an id
+func Preserve() h.Node {
+	return attr("hx-preserve", "true")
+}
+
+// Validate forces the element to be validated before a request is made
+func Validate() h.Node {
+	return attr("hx-validate", "true")
+}
@@ -0,0 +1,135 @@
+package hx
+
+import (
+	"encoding/json"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"strings"
+)
+
+// IsRequest returns true if the request is made by htmx
+func IsRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted returns true if the request is made by an element using hx-boost
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// IsHistoryRestore returns true if the request is made because the history cache was missing
+func IsHistoryRestore(r *http.Request) bool {
+	return r.Header.Get("HX-History-Restore-Request") == "true"
+}
+
+// CurrentURL returns the url of the page the request was made from
+func CurrentURL(r *http.Request) string {
+	return r.Header.Get("HX-Current-URL")
+}
+
+// TargetID returns the id of the target element, if it has one
+func TargetID(r *http.Request) string {
+	return r.Header.Get("HX-Target")
+}
+
+// TriggerID returns the id of the element that triggered the request, if it has one
+func TriggerID(r *http.Request) string {
+	return r.Header.Get("HX-Trigger")
+}
+
+// TriggerName returns the name of the element that triggered the request, if it has one
+func TriggerName(r *http.Request) string {
+	return r.Header.Get("HX-Trigger-Name")
+}
+
+// PromptResponse returns the response from the user when hx-prompt is used
+func PromptResponse(r *http.Request) string {
+	return r.Header.Get("HX-Prompt")
+}
+
+// SetTrigger triggers the supplied client-side events as soon as the response is received
+func SetTrigger(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger", strings.Join(events, ", "))
+}
+
+// SetTriggerDetail triggers the supplied client-side events, with details, as soon as the response is received
+func SetTriggerDetail(w http.ResponseWriter, events map[string]any) error {
+	return setJSON(w, "HX-Trigger", events)
+}
+
+// SetTriggerAfterSwap triggers the supplied client-side events after the content has been swapped
+func SetTriggerAfterSwap(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Swap", strings.Join(events, ", "))
+}
+
+// SetTriggerAfterSettle triggers the supplied client-side events after the content has been settled
+func SetTriggerAfterSettle(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger-After-Settle", strings.Join(events, ", "))
+}
+
+func setJSON(w http.ResponseWriter, header string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(header, string(b))
+	return nil
+}
+
+// SetRedirect makes the client do a full redirect to the supplied url
+func SetRedirect(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Redirect", url)
+}
+
+// SetLocation makes the client navigate to the supplied url without reloading the page
+func SetLocation(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Location", url)
+}
+
+// SetRefresh makes the client do a full refresh of the page
+func SetRefresh(w http.ResponseWriter) {
+	w.Header().Set("HX-Refresh", "true")
+}
+
+// SetPushURL pushes the supplied url into the browser history
+func SetPushURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Push-Url", url)
+}
+
+// SetReplaceURL replaces the current url in the browser history
+func SetReplaceURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Replace-Url", url)
+}
+
+// SetRetarget changes the target of the response
+func SetRetarget(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Retarget", selector)
+}
+
+// SetReswap changes how the response is swapped
+func SetReswap(w http.ResponseWriter, s SwapStrategy, modifiers ...SwapModifier) {
+	w.Header().Set("HX-Reswap", swapValue(s, modifiers))
+}
+
+// SetReselect changes which part of the response that is swapped
+func SetReselect(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Reselect", selector)
+}
+
+// Render writes the fragment if the request is made by htmx and the full page otherwise. Boosted requests and
+// history restore requests always get the full page
+//
+// Example:
+//
+//	func todos(w http.ResponseWriter, r *http.Request) {
+//	  list := TodoList(items)
+//	  _, _ = hx.Render(w, r, Page(list), list)
+//	}
+func Render(w http.ResponseWriter, r *http.Request, page h.RootNode, fragment h.Node) (int, error) {
+	w.Header().Add("Vary", "HX-Request")
+	ww := h.WithContext(r.Context(), w)
+	if IsRequest(r) && !IsBoosted(r) && !IsHistoryRestore(r) {
+		return h.Fragment(fragment)(ww)
+	}
+	return page(ww)
+}
@@ -0,0 +1,239 @@
+package i18n
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Message is a translated message. Messages without plural forms only use Other
+type Message map[Plural]string
+
+// Form returns the plural form of the message. If the message doesn't have the form then Other is used and, if
+// that's missing as well, any other form. Returns false if the message doesn't have any non-empty form
+func (m Message) Form(p Plural) (string, bool) {
+	for _, f := range []Plural{p, Other, One, Few, Many, Two, Zero} {
+		if s := m[f]; s != "" {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Catalog contains all messages of a single locale
+type Catalog map[string]Message
+
+// isPlural returns true if all keys of the JSON object are plural categories
+func isPlural(obj map[string]json.RawMessage) bool {
+	for k := range obj {
+		switch Plural(k) {
+		case Zero, One, Two, Few, Many, Other:
+		default:
+			return false
+		}
+	}
+	return len(obj) > 0
+}
+
+// readJSON adds all messages in the JSON object to the catalog. Nested objects are either plural forms or
+// a namespace, where the keys are joined with a dot
+func (c Catalog) readJSON(prefix string, obj map[string]json.RawMessage) error {
+	for k, raw := range obj {
+		key := prefix + k
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			c[key] = Message{Other: s}
+			continue
+		}
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &nested); err != nil {
+			return fmt.Errorf("i18n: message %s must be a string or an object", key)
+		}
+		if !isPlural(nested) {
+			if err := c.readJSON(key+".", nested); err != nil {
+				return err
+			}
+			continue
+		}
+		m := Message{}
+		for p, v := range nested {
+			var s string
+			if err := json.Unmarshal(v, &s); err != nil {
+				return fmt.Errorf("i18n: plural form %s of message %s must be a string", p, key)
+			}
+			m[Plural(p)] = s
+		}
+		c[key] = m
+	}
+	return nil
+}
+
+// ParseJSON reads a catalog from a JSON file. A message is either a string or an object with the plural forms
+// of the message. All other objects are namespaces.
+//
+// Example:
+//
+//	{
+//	  "greeting": "Hello {name}",
+//	  "cart": {
+//	    "items": {"one": "{count} item", "other": "{count} items"}
+//	  }
+//	}
+func ParseJSON(r io.Reader) (Catalog, error) {
+	var obj map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, err
+	}
+	c := Catalog{}
+	if err := c.readJSON("", obj); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// poEntry is a single entry in a PO file
+type poEntry struct {
+	id     string
+	plural bool
+	str    map[int]string
+	fuzzy  bool
+}
+
+// ParsePO reads a catalog from a gettext PO file. The msgstr[n] entries of a plural message are mapped to the
+// plural categories of the locale in CLDR order, for example one, few, many and other for Russian. Fuzzy entries
+// and the header are ignored
+func ParsePO(r io.Reader, locale string) (Catalog, error) {
+	c := Catalog{}
+	categories := pluralRulesOf(locale).categories
+
+	var e *poEntry
+	// appendTo is used by continuation lines to append to the previous string
+	var appendTo func(s string)
+	add := func() {
+		if e == nil || e.id == "" || e.fuzzy {
+			return
+		}
+		m := Message{}
+		for n, s := range e.str {
+			if s == "" {
+				continue
+			}
+			switch {
+			case !e.plural:
+				m[Other] = s
+			case n < len(categories):
+				m[categories[n]] = s
+			}
+		}
+		if len(m) > 0 {
+			c[e.id] = m
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	fuzzy := false
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+			continue
+		case strings.HasPrefix(text, "#,"):
+			fuzzy = fuzzy || strings.Contains(text, "fuzzy")
+			continue
+		case strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, `"`):
+			if appendTo == nil {
+				return nil, fmt.Errorf("i18n: unexpected string on line %d", line)
+			}
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, fmt.Errorf("i18n: invalid string on line %d: %w", line, err)
+			}
+			appendTo(s)
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(text, " ")
+		s, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: invalid string on line %d: %w", line, err)
+		}
+		switch {
+		case keyword == "msgctxt":
+			// contexts are not supported. The context is read and ignored
+			appendTo = func(string) {}
+		case keyword == "msgid":
+			add()
+			e = &poEntry{id: s, str: map[int]string{}, fuzzy: fuzzy}
+			fuzzy = false
+			entry := e
+			appendTo = func(s string) { entry.id += s }
+		case keyword == "msgid_plural" && e != nil:
+			e.plural = true
+			appendTo = func(string) {}
+		case strings.HasPrefix(keyword, "msgstr") && e != nil:
+			n := 0
+			if idx, ok := strings.CutPrefix(keyword, "msgstr"); ok && idx != "" {
+				if !strings.HasPrefix(idx, "[") || !strings.HasSuffix(idx, "]") {
+					return nil, fmt.Errorf("i18n: unexpected keyword %s on line %d", keyword, line)
+				}
+				if n, err = strconv.Atoi(idx[1 : len(idx)-1]); err != nil {
+					return nil, fmt.Errorf("i18n: invalid plural index on line %d", line)
+				}
+			}
+			e.str[n] = s
+			entry := e
+			appendTo = func(s string) { entry.str[n] += s }
+		default:
+			return nil, fmt.Errorf("i18n: unexpected keyword %s on line %d", keyword, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	add()
+	return c, nil
+}
+
+// LoadFS reads all catalogs in the root of the file system. The locale of a catalog is the name of the file without
+// the extension, for example "sv-SE.json" or "en.po"
+func (b *Bundle) LoadFS(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := path.Ext(entry.Name())
+		if ext != ".json" && ext != ".po" {
+			continue
+		}
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		f, err := fsys.Open(entry.Name())
+		if err != nil {
+			return err
+		}
+		var c Catalog
+		if ext == ".json" {
+			c, err = ParseJSON(f)
+		} else {
+			c, err = ParsePO(f, locale)
+		}
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		b.Add(locale, c)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+)
+
+const czechPO = `msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;\n"
+
+msgid "files"
+msgid_plural "files"
+msgstr[0] "{count} soubor"
+msgstr[1] "{count} soubory"
+msgstr[2] "{count} souborů"
+`
+
+func TestParsePOPluralForms(t *testing.T) {
+	c, err := ParsePO(strings.NewReader(czechPO), "cs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewBundle("cs")
+	b.Add("cs", c)
+	tests := map[int]string{1: "1 soubor", 3: "3 soubory", 5: "5 souborů", 22: "22 souborů", 100: "100 souborů"}
+	for n, expected := range tests {
+		if actual := b.TranslatePlural("cs", "files", n); actual != expected {
+			t.Errorf("expected %q for %d but was %q", expected, n, actual)
+		}
+	}
+}
+
+func TestTranslatePluralMissingForm(t *testing.T) {
+	b := NewBundle("ru")
+	b.Add("ru", Catalog{"files": Message{One: "{count} файл"}})
+	if actual := b.TranslatePlural("ru", "files", 5); actual != "5 файл" {
+		t.Errorf("expected another form to be used but was %q", actual)
+	}
+	b.Add("ru", Catalog{"empty": Message{}})
+	if actual := b.TranslatePlural("ru", "empty", 5); actual != "empty" {
+		t.Errorf("expected the key to be used but was %q", actual)
+	}
+}
+
+func TestRegisterWhileRendering(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			RegisterPluralRule("x-test", func(int) Plural { return Other }, Other)
+			RegisterFormat("x-test", FormatOf("en"))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = PluralOf("x-test", i)
+		_ = FormatNumber("x-test", float64(i), 0)
+	}
+	<-done
+}
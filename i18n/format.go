@@ -0,0 +1,449 @@
+package i18n
+
+import (
+	"context"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nbsp is a non-breaking space, used between numbers and units in many locales
+const nbsp = "\u00a0"
+
+// nnbsp is a narrow non-breaking space, used by French
+const nnbsp = "\u202f"
+
+// Format contains the information needed to format numbers, currencies and dates in a locale
+type Format struct {
+	// Decimal is the decimal separator
+	Decimal string
+	// Group is the separator between groups of thousands
+	Group string
+	// Percent is the pattern of a percentage, where {0} is the number
+	Percent string
+	// Currency is the pattern of a currency amount, where {0} is the number and {1} is the symbol
+	Currency string
+	// Months are the names of the months as they are written in a date
+	Months [12]string
+	// DateShort is a time layout, such as "2006-01-02", used by the DateShort style
+	DateShort string
+	// DateLong is the pattern of a date, where {0} is the day, {1} the name of the month and {2} the year
+	DateLong string
+	// Time is a time layout, such as "15:04", used when the time is formatted
+	Time string
+	// Future and Past are the patterns of a relative time, where {0} is the duration, for example "in {0}"
+	Future string
+	Past   string
+	// Units contains the plural forms of each relative time unit, where {count} is the number
+	Units map[Unit]Message
+}
+
+// Unit is a unit of relative time
+type Unit string
+
+const (
+	Second Unit = "second"
+	Minute Unit = "minute"
+	Hour   Unit = "hour"
+	Day    Unit = "day"
+	Week   Unit = "week"
+	Month  Unit = "month"
+	Year   Unit = "year"
+)
+
+var (
+	formatsMutex sync.RWMutex
+	// formats contains the formats of all known locales, indexed by locale or language
+	formats = map[string]Format{}
+)
+
+// RegisterFormat registers the format of a locale, such as "en-GB", or a language, such as "en". It's safe to call
+// this while other goroutines are rendering
+func RegisterFormat(locale string, f Format) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	formats[normalize(locale)] = f
+}
+
+// FormatOf returns the format of the supplied locale. The English format is used if the locale isn't known
+func FormatOf(locale string) Format {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	if f, ok := formats[normalize(locale)]; ok {
+		return f
+	}
+	if f, ok := formats[language(locale)]; ok {
+		return f
+	}
+	return formats["en"]
+}
+
+// CurrencySymbols contains the symbol of each currency code. The code itself is used for all other currencies.
+// The map is read without a lock, so it must only be changed from an init function
+var CurrencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"SEK": "kr",
+	"NOK": "kr",
+	"DKK": "kr",
+	"RUB": "₽",
+}
+
+// currencyDecimals contains the number of decimals of currencies that doesn't use two decimals
+var currencyDecimals = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"ISK": 0,
+	"CLP": 0,
+}
+
+// localeOf returns the locale of the context, or English if the context has no locale
+func localeOf(ctx context.Context) string {
+	if l := Locale(ctx); l != "" {
+		return l
+	}
+	return "en"
+}
+
+// isZero returns true if the formatted number only contains zeros
+func isZero(s string) bool {
+	return strings.Trim(s, "0.") == ""
+}
+
+// FormatNumber formats the number with the supplied number of decimals using the separators of the locale
+//
+// Example:
+//
+//	FormatNumber("sv", 1234567.891, 2) // 1 234 567,89
+func FormatNumber(locale string, v float64, decimals int) string {
+	f := FormatOf(locale)
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(s, ".")
+
+	sb := strings.Builder{}
+	if v < 0 && !isZero(s) {
+		sb.WriteByte('-')
+	}
+	for i := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(f.Group)
+		}
+		sb.WriteByte(integer[i])
+	}
+	if fraction != "" {
+		sb.WriteString(f.Decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
+
+// FormatPercent formats a fraction, where 1.0 is 100%, as a percentage
+func FormatPercent(locale string, v float64, decimals int) string {
+	return strings.Replace(FormatOf(locale).Percent, "{0}", FormatNumber(locale, v*100, decimals), 1)
+}
+
+// FormatCurrency formats an amount in the supplied currency, such as "EUR" or "SEK"
+func FormatCurrency(locale string, amount float64, currency string) string {
+	decimals, ok := currencyDecimals[currency]
+	if !ok {
+		decimals = 2
+	}
+	symbol, ok := CurrencySymbols[currency]
+	if !ok {
+		symbol = currency
+	}
+	number := FormatNumber(locale, math.Abs(amount), decimals)
+	pattern := FormatOf(locale).Currency
+	// a symbol made of letters, such as "kr", is separated from the number, like "kr 199.50" instead of "kr199.50"
+	if r, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(r) {
+		pattern = strings.Replace(pattern, "{1}{0}", "{1}"+nbsp+"{0}", 1)
+	}
+	if r, _ := utf8.DecodeRuneInString(symbol); unicode.IsLetter(r) {
+		pattern = strings.Replace(pattern, "{0}{1}", "{0}"+nbsp+"{1}", 1)
+	}
+	result := strings.NewReplacer("{0}", number, "{1}", symbol).Replace(pattern)
+	if amount < 0 && !isZero(strconv.FormatFloat(-amount, 'f', decimals, 64)) {
+		result = "-" + result
+	}
+	return result
+}
+
+// DateStyle is how a date is formatted
+type DateStyle int
+
+const (
+	// DateShort formats the date using only numbers
+	DateShort DateStyle = iota
+	// DateLong formats the date with the name of the month
+	DateLong
+	// DateTimeShort formats the date using only numbers, followed by the time
+	DateTimeShort
+	// DateTimeLong formats the date with the name of the month, followed by the time
+	DateTimeLong
+)
+
+// FormatDate formats the date in the supplied style
+func FormatDate(locale string, t time.Time, style DateStyle) string {
+	f := FormatOf(locale)
+	var date string
+	switch style {
+	case DateShort, DateTimeShort:
+		date = t.Format(f.DateShort)
+	default:
+		date = strings.NewReplacer(
+			"{0}", strconv.Itoa(t.Day()),
+			"{1}", f.Months[t.Month()-1],
+			"{2}", strconv.Itoa(t.Year()),
+		).Replace(f.DateLong)
+	}
+	if style == DateTimeShort || style == DateTimeLong {
+		date += " " + t.Format(f.Time)
+	}
+	return date
+}
+
+// FormatRelative formats the time relative to now, for example "3 days ago" or "in 2 hours"
+func FormatRelative(locale string, t time.Time, now time.Time) string {
+	d := t.Sub(now)
+	future := d >= 0
+	if !future {
+		d = -d
+	}
+
+	var unit Unit
+	var n int
+	switch {
+	case d < time.Minute:
+		unit, n = Second, int(d/time.Second)
+	case d < time.Hour:
+		unit, n = Minute, int(d/time.Minute)
+	case d < 24*time.Hour:
+		unit, n = Hour, int(d/time.Hour)
+	case d < 7*24*time.Hour:
+		unit, n = Day, int(d/(24*time.Hour))
+	case d < 30*24*time.Hour:
+		unit, n = Week, int(d/(7*24*time.Hour))
+	case d < 365*24*time.Hour:
+		unit, n = Month, int(d/(30*24*time.Hour))
+	default:
+		unit, n = Year, int(d/(365*24*time.Hour))
+	}
+
+	f := FormatOf(locale)
+	m := f.Units[unit]
+	s, _ := m.Form(PluralOf(locale, n))
+	s = strings.Replace(s, "{count}", FormatNumber(locale, float64(n), 0), 1)
+	pattern := f.Past
+	if future {
+		pattern = f.Future
+	}
+	return strings.Replace(pattern, "{0}", s, 1)
+}
+
+// Number emits the number formatted using the locale of the render context
+func Number(v float64, decimals int) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Text(FormatNumber(localeOf(ctx), v, decimals))
+	})
+}
+
+// Percent emits a fraction, where 1.0 is 100%, as a percentage using the locale of the render context
+func Percent(v float64, decimals int) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Text(FormatPercent(localeOf(ctx), v, decimals))
+	})
+}
+
+// Currency emits the amount in the supplied currency using the locale of the render context
+//
+// Example:
+//
+//	h.P(i18n.Currency(199.5, "SEK")) // 199,50 kr in Swedish and kr 199.50 in English
+func Currency(amount float64, currency string) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Text(html.EscapeString(FormatCurrency(localeOf(ctx), amount, currency)))
+	})
+}
+
+// Date emits a time tag with the date formatted using the locale of the render context. The datetime attribute
+// contains the date in a machine-readable format
+//
+// Example:
+//
+//	i18n.Date(post.Published, i18n.DateLong) // <time datetime="2024-03-01">1 mars 2024</time>
+func Date(t time.Time, style DateStyle) h.Node {
+	datetime := t.Format(time.DateOnly)
+	if style == DateTimeShort || style == DateTimeLong {
+		datetime = t.Format(time.RFC3339)
+	}
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Time(a.DateTime(datetime), h.Text(html.EscapeString(FormatDate(localeOf(ctx), t, style))))
+	})
+}
+
+// RelativeTime emits a time tag with the time relative to now, for example "3 days ago", using the locale of
+// the render context. The datetime attribute contains the time in a machine-readable format
+func RelativeTime(t time.Time, now time.Time) h.Node {
+	datetime := t.Format(time.RFC3339)
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Time(a.DateTime(datetime), h.Text(html.EscapeString(FormatRelative(localeOf(ctx), t, now))))
+	})
+}
+
+func init() {
+	RegisterFormat("en", Format{
+		Decimal:  ".",
+		Group:    ",",
+		Percent:  "{0}%",
+		Currency: "{1}{0}",
+		Months: [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September",
+			"October", "November", "December"},
+		DateShort: "1/2/2006",
+		DateLong:  "{1} {0}, {2}",
+		Time:      "3:04 PM",
+		Future:    "in {0}",
+		Past:      "{0} ago",
+		Units: map[Unit]Message{
+			Second: {One: "{count} second", Other: "{count} seconds"},
+			Minute: {One: "{count} minute", Other: "{count} minutes"},
+			Hour:   {One: "{count} hour", Other: "{count} hours"},
+			Day:    {One: "{count} day", Other: "{count} days"},
+			Week:   {One: "{count} week", Other: "{count} weeks"},
+			Month:  {One: "{count} month", Other: "{count} months"},
+			Year:   {One: "{count} year", Other: "{count} years"},
+		},
+	})
+	RegisterFormat("en-GB", Format{
+		Decimal:  ".",
+		Group:    ",",
+		Percent:  "{0}%",
+		Currency: "{1}{0}",
+		Months: [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September",
+			"October", "November", "December"},
+		DateShort: "02/01/2006",
+		DateLong:  "{0} {1} {2}",
+		Time:      "15:04",
+		Future:    "in {0}",
+		Past:      "{0} ago",
+		Units:     formats["en"].Units,
+	})
+	RegisterFormat("sv", Format{
+		Decimal:  ",",
+		Group:    nbsp,
+		Percent:  "{0}" + nbsp + "%",
+		Currency: "{0}" + nbsp + "{1}",
+		Months: [12]string{"januari", "februari", "mars", "april", "maj", "juni", "juli", "augusti", "september",
+			"oktober", "november", "december"},
+		DateShort: "2006-01-02",
+		DateLong:  "{0} {1} {2}",
+		Time:      "15:04",
+		Future:    "om {0}",
+		Past:      "för {0} sedan",
+		Units: map[Unit]Message{
+			Second: {One: "{count} sekund", Other: "{count} sekunder"},
+			Minute: {One: "{count} minut", Other: "{count} minuter"},
+			Hour:   {One: "{count} timme", Other: "{count} timmar"},
+			Day:    {One: "{count} dag", Other: "{count} dagar"},
+			Week:   {One: "{count} vecka", Other: "{count} veckor"},
+			Month:  {One: "{count} månad", Other: "{count} månader"},
+			Year:   {One: "{count} år", Other: "{count} år"},
+		},
+	})
+	RegisterFormat("de", Format{
+		Decimal:  ",",
+		Group:    ".",
+		Percent:  "{0}" + nbsp + "%",
+		Currency: "{0}" + nbsp + "{1}",
+		Months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September",
+			"Oktober", "November", "Dezember"},
+		DateShort: "02.01.2006",
+		DateLong:  "{0}. {1} {2}",
+		Time:      "15:04",
+		Future:    "in {0}",
+		Past:      "vor {0}",
+		Units: map[Unit]Message{
+			Second: {One: "{count} Sekunde", Other: "{count} Sekunden"},
+			Minute: {One: "{count} Minute", Other: "{count} Minuten"},
+			Hour:   {One: "{count} Stunde", Other: "{count} Stunden"},
+			Day:    {One: "{count} Tag", Other: "{count} Tagen"},
+			Week:   {One: "{count} Woche", Other: "{count} Wochen"},
+			Month:  {One: "{count} Monat", Other: "{count} Monaten"},
+			Year:   {One: "{count} Jahr", Other: "{count} Jahren"},
+		},
+	})
+	RegisterFormat("fr", Format{
+		Decimal:  ",",
+		Group:    nnbsp,
+		Percent:  "{0}" + nnbsp + "%",
+		Currency: "{0}" + nbsp + "{1}",
+		Months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre",
+			"octobre", "novembre", "décembre"},
+		DateShort: "02/01/2006",
+		DateLong:  "{0} {1} {2}",
+		Time:      "15:04",
+		Future:    "dans {0}",
+		Past:      "il y a {0}",
+		Units: map[Unit]Message{
+			Second: {One: "{count} seconde", Other: "{count} secondes"},
+			Minute: {One: "{count} minute", Other: "{count} minutes"},
+			Hour:   {One: "{count} heure", Other: "{count} heures"},
+			Day:    {One: "{count} jour", Other: "{count} jours"},
+			Week:   {One: "{count} semaine", Other: "{count} semaines"},
+			Month:  {One: "{count} mois", Other: "{count} mois"},
+			Year:   {One: "{count} an", Other: "{count} ans"},
+		},
+	})
+	RegisterFormat("es", Format{
+		Decimal:  ",",
+		Group:    ".",
+		Percent:  "{0}" + nbsp + "%",
+		Currency: "{0}" + nbsp + "{1}",
+		Months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre",
+			"octubre", "noviembre", "diciembre"},
+		DateShort: "2/1/2006",
+		DateLong:  "{0} de {1} de {2}",
+		Time:      "15:04",
+		Future:    "dentro de {0}",
+		Past:      "hace {0}",
+		Units: map[Unit]Message{
+			Second: {One: "{count} segundo", Other: "{count} segundos"},
+			Minute: {One: "{count} minuto", Other: "{count} minutos"},
+			Hour:   {One: "{count} hora", Other: "{count} horas"},
+			Day:    {One: "{count} día", Other: "{count} días"},
+			Week:   {One: "{count} semana", Other: "{count} semanas"},
+			Month:  {One: "{count} mes", Other: "{count} meses"},
+			Year:   {One: "{count} año", Other: "{count} años"},
+		},
+	})
+	RegisterFormat("ru", Format{
+		Decimal:  ",",
+		Group:    nbsp,
+		Percent:  "{0}" + nbsp + "%",
+		Currency: "{0}" + nbsp + "{1}",
+		Months: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября",
+			"октября", "ноября", "декабря"},
+		DateShort: "02.01.2006",
+		DateLong:  "{0} {1} {2} г.",
+		Time:      "15:04",
+		Future:    "через {0}",
+		Past:      "{0} назад",
+		Units: map[Unit]Message{
+			Second: {One: "{count} секунду", Few: "{count} секунды", Many: "{count} секунд"},
+			Minute: {One: "{count} минуту", Few: "{count} минуты", Many: "{count} минут"},
+			Hour:   {One: "{count} час", Few: "{count} часа", Many: "{count} часов"},
+			Day:    {One: "{count} день", Few: "{count} дня", Many: "{count} дней"},
+			Week:   {One: "{count} неделю", Few: "{count} недели", Many: "{count} недель"},
+			Month:  {One: "{count} месяц", Few: "{count} месяца", Many: "{count} месяцев"},
+			Year:   {One: "{count} год", Few: "{count} года", Many: "{count} лет"},
+		},
+	})
+}
@@ -0,0 +1,24 @@
+package i18n
+
+import "testing"
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		locale   string
+		amount   float64
+		currency string
+		expected string
+	}{
+		{"en", 199.5, "SEK", "kr" + nbsp + "199.50"},
+		{"en", 199.5, "USD", "$199.50"},
+		{"en", -5, "EUR", "-€5.00"},
+		{"en", 1000, "CHF", "CHF" + nbsp + "1,000.00"},
+		{"sv", 199.5, "SEK", "199,50" + nbsp + "kr"},
+		{"de", 1000, "JPY", "1.000" + nbsp + "¥"},
+	}
+	for _, test := range tests {
+		if actual := FormatCurrency(test.locale, test.amount, test.currency); actual != test.expected {
+			t.Errorf("expected %q for %s but was %q", test.expected, test.locale, actual)
+		}
+	}
+}
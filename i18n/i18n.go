@@ -0,0 +1,357 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// rtl contains all languages that are written from right to left
+var rtl = map[string]bool{
+	"ar": true, "he": true, "fa": true, "ur": true, "ps": true, "yi": true, "dv": true, "ug": true, "ckb": true, "sd": true,
+}
+
+// normalize converts a locale, such as "sv_se", into the canonical form "sv-SE"
+func normalize(locale string) string {
+	parts := strings.Split(strings.ReplaceAll(locale, "_", "-"), "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// language returns the language part of a locale, for example "sv" for "sv-SE"
+func language(locale string) string {
+	lang, _, _ := strings.Cut(normalize(locale), "-")
+	return lang
+}
+
+// Dir returns the direction of the text in the supplied locale, which is either "rtl" or "ltr"
+func Dir(locale string) string {
+	if rtl[language(locale)] {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// Arg is a named parameter used when a message is interpolated
+type Arg struct {
+	Name  string
+	Value any
+}
+
+// P creates a named parameter. The parameter replaces {name} in a message
+func P(name string, value any) Arg {
+	return Arg{Name: name, Value: value}
+}
+
+// Bundle contains the catalogs of all supported locales
+//
+// Example:
+//
+//	//go:embed locales
+//	var locales embed.FS
+//
+//	var bundle = i18n.NewBundle("en")
+//
+//	func main() {
+//	  if err := bundle.LoadFS(must(fs.Sub(locales, "locales"))); err != nil {
+//	    log.Fatal(err)
+//	  }
+//	  http.Handle("/", bundle.Middleware(http.HandlerFunc(index)))
+//	}
+type Bundle struct {
+	fallback string
+	mutex    sync.RWMutex
+	catalogs map[string]Catalog
+}
+
+// NewBundle creates a new bundle. The fallback locale is used when no catalog matches the locale of the
+// user and for messages that are missing in the catalog of the user
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		fallback: normalize(fallback),
+		catalogs: make(map[string]Catalog),
+	}
+}
+
+// Add adds the messages to the catalog of the supplied locale. Existing messages are replaced
+func (b *Bundle) Add(locale string, c Catalog) {
+	locale = normalize(locale)
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	existing, ok := b.catalogs[locale]
+	if !ok {
+		existing = Catalog{}
+		b.catalogs[locale] = existing
+	}
+	for k, v := range c {
+		existing[k] = v
+	}
+}
+
+// Locales returns all locales that have a catalog, in alphabetical order
+func (b *Bundle) Locales() []string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	result := make([]string, 0, len(b.catalogs))
+	for l := range b.catalogs {
+		result = append(result, l)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Match returns the supported locale that best matches the supplied locale. A locale matches if it's the same, or
+// if the language is the same. Returns an empty string if no locale matches
+func (b *Bundle) Match(locale string) string {
+	locale = normalize(locale)
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if _, ok := b.catalogs[locale]; ok {
+		return locale
+	}
+	lang := language(locale)
+	if _, ok := b.catalogs[lang]; ok {
+		return lang
+	}
+	best := ""
+	for l := range b.catalogs {
+		if language(l) == lang && (best == "" || l < best) {
+			best = l
+		}
+	}
+	return best
+}
+
+// Negotiate returns the supported locale that best matches the Accept-Language header. Returns the fallback
+// locale if no locale matches
+func (b *Bundle) Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, v := range strings.Split(acceptLanguage, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(v), ";")
+		if locale == "" || locale == "*" {
+			continue
+		}
+		q := 1.0
+		if s, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	for _, c := range candidates {
+		if l := b.Match(c.locale); l != "" {
+			return l
+		}
+	}
+	return b.fallback
+}
+
+// message returns the message for the key in the supplied locale, or in the fallback locale if it's missing
+func (b *Bundle) message(locale string, key string) (Message, string, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	for _, l := range []string{locale, language(locale), b.fallback} {
+		if m, ok := b.catalogs[l][key]; ok {
+			return m, l, true
+		}
+	}
+	return nil, "", false
+}
+
+// interpolate replaces all {name} placeholders with the escaped value of the named parameter. Unknown
+// placeholders are left as they are. Use {{ to write a single {
+func interpolate(message string, args []Arg) string {
+	if !strings.Contains(message, "{") {
+		return message
+	}
+	sb := strings.Builder{}
+	for {
+		i := strings.IndexByte(message, '{')
+		if i == -1 {
+			sb.WriteString(message)
+			return sb.String()
+		}
+		sb.WriteString(message[:i])
+		message = message[i:]
+		if strings.HasPrefix(message, "{{") {
+			sb.WriteByte('{')
+			message = message[2:]
+			continue
+		}
+		end := strings.IndexByte(message, '}')
+		if end == -1 {
+			sb.WriteString(message)
+			return sb.String()
+		}
+		name := message[1:end]
+		found := false
+		for _, a := range args {
+			if a.Name == name {
+				sb.WriteString(html.EscapeString(fmt.Sprint(a.Value)))
+				found = true
+				break
+			}
+		}
+		if !found {
+			sb.WriteString(message[:end+1])
+		}
+		message = message[end+1:]
+	}
+}
+
+// Translate returns the message for the key in the supplied locale. The parameters are escaped before they are put
+// in the message, but the message itself is trusted. The key itself is returned, escaped, if the message is missing
+func (b *Bundle) Translate(locale string, key string, args ...Arg) string {
+	m, _, ok := b.message(normalize(locale), key)
+	if !ok {
+		return html.EscapeString(key)
+	}
+	s, ok := m.Form(Other)
+	if !ok {
+		return html.EscapeString(key)
+	}
+	return interpolate(s, args)
+}
+
+// TranslatePlural returns the plural form of the message for the key that matches the count. The count is
+// available as the {count} parameter. Another form is used if the message doesn't have the form of the count, and the
+// key itself is returned, escaped, if the message is missing
+func (b *Bundle) TranslatePlural(locale string, key string, count int, args ...Arg) string {
+	m, l, ok := b.message(normalize(locale), key)
+	if !ok {
+		return html.EscapeString(key)
+	}
+	s, ok := m.Form(PluralOf(l, count))
+	if !ok {
+		return html.EscapeString(key)
+	}
+	return interpolate(s, append([]Arg{P("count", count)}, args...))
+}
+
+// localizer is the bundle and locale used when rendering
+type localizer struct {
+	bundle *Bundle
+	locale string
+}
+
+// localizerKey is the context key for the localizer
+type localizerKey struct{}
+
+// WithLocale returns a context where all translations are made using the supplied locale. The lang and dir
+// attributes of the html tag are set automatically, unless they are set by the application
+func (b *Bundle) WithLocale(ctx context.Context, locale string) context.Context {
+	if l := b.Match(locale); l != "" {
+		locale = l
+	} else {
+		locale = b.fallback
+	}
+	ctx = context.WithValue(ctx, localizerKey{}, &localizer{bundle: b, locale: locale})
+	return h.WithTagHook(ctx, func(t *h.OpenTag) h.Node {
+		lang, ok := t.Attr("lang")
+		if !ok {
+			lang = locale
+			t.SetAttr("lang", lang)
+		}
+		if _, ok := t.Attr("dir"); !ok {
+			t.SetAttr("dir", Dir(lang))
+		}
+		return nil
+	}, "html")
+}
+
+// Middleware picks the locale of the user from the Accept-Language header
+func (b *Bundle) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := b.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", locale)
+		next.ServeHTTP(w, r.WithContext(b.WithLocale(r.Context(), locale)))
+	})
+}
+
+// Locale returns the locale of the supplied context. Returns an empty string if no locale is set
+func Locale(ctx context.Context) string {
+	if l, ok := ctx.Value(localizerKey{}).(*localizer); ok {
+		return l.locale
+	}
+	return ""
+}
+
+// String returns the translated message for the key using the locale of the context. The key is returned if the
+// context has no locale
+func String(ctx context.Context, key string, args ...Arg) string {
+	l, ok := ctx.Value(localizerKey{}).(*localizer)
+	if !ok {
+		return html.EscapeString(key)
+	}
+	return l.bundle.Translate(l.locale, key, args...)
+}
+
+// PluralString returns the plural form of the message for the key, that matches the count, using the locale of
+// the context
+func PluralString(ctx context.Context, key string, count int, args ...Arg) string {
+	l, ok := ctx.Value(localizerKey{}).(*localizer)
+	if !ok {
+		return html.EscapeString(key)
+	}
+	return l.bundle.TranslatePlural(l.locale, key, count, args...)
+}
+
+// T emits the translated message for the key using the locale of the render context
+//
+// Example:
+//
+//	h.P(i18n.T("greeting", i18n.P("name", user.Name)))
+func T(key string, args ...Arg) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Text(String(ctx, key, args...))
+	})
+}
+
+// N emits the plural form of the translated message for the key that matches the count
+//
+// Example:
+//
+//	h.P(i18n.N("cart.items", len(items)))
+func N(key string, count int, args ...Arg) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		return h.Text(PluralString(ctx, key, count, args...))
+	})
+}
+
+// Attr emits an attribute with the translated message for the key as value. The message is escaped for use as
+// an attribute value, which means that entities in it are kept but quotes can't end the attribute
+//
+// Example:
+//
+//	h.Input(i18n.Attr("placeholder", "search.placeholder"))
+func Attr(name string, key string, args ...Arg) h.Node {
+	return h.FromContext(func(ctx context.Context) h.Node {
+		// the arguments are already escaped, so everything is unescaped first to avoid escaping them twice
+		return h.Attrib(name, html.EscapeString(html.UnescapeString(String(ctx, key, args...))))
+	})
+}
@@ -0,0 +1,38 @@
+package i18n
+
+import (
+	"context"
+	"github.com/westcoastcode-se/gohtml/h"
+	"strings"
+	"testing"
+)
+
+func TestAttr(t *testing.T) {
+	b := NewBundle("sv")
+	b.Add("sv", Catalog{
+		"search": Message{Other: `Sök "allt"`},
+		"entity": Message{Other: `Tom &amp; Jerry`},
+		"name":   Message{Other: `Hej {name}`},
+	})
+	tests := []struct {
+		name     string
+		node     h.Node
+		expected string
+	}{
+		{"quote", Attr("placeholder", "search"), `<input placeholder="Sök &#34;allt&#34;"/>`},
+		{"entity", Attr("placeholder", "entity"), `<input placeholder="Tom &amp; Jerry"/>`},
+		{"argument", Attr("placeholder", "name", P("name", `"><script>`)),
+			`<input placeholder="Hej &#34;&gt;&lt;script&gt;"/>`},
+		{"missing key", Attr("placeholder", `"x`), `<input placeholder="&#34;x"/>`},
+	}
+	ctx := b.WithLocale(context.Background(), "sv")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sb := &strings.Builder{}
+			h.Input(test.node)(0, h.WithContext(ctx, sb))
+			if sb.String() != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, sb.String())
+			}
+		})
+	}
+}
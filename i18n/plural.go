@@ -0,0 +1,177 @@
+package i18n
+
+import "sync"
+
+// Plural is a CLDR plural category
+type Plural string
+
+const (
+	Zero  Plural = "zero"
+	One   Plural = "one"
+	Two   Plural = "two"
+	Few   Plural = "few"
+	Many  Plural = "many"
+	Other Plural = "other"
+)
+
+// PluralRule returns the plural category of a count
+type PluralRule func(n int) Plural
+
+// pluralRules contains the rule and the categories used by a language. The categories are in the same order as the
+// msgstr[n] entries in a PO file
+type pluralRules struct {
+	rule       PluralRule
+	categories []Plural
+}
+
+var (
+	rulesMutex sync.RWMutex
+	// rules contains the CLDR cardinal plural rules for integers, indexed by the language part of a locale
+	rules = map[string]pluralRules{}
+)
+
+// RegisterPluralRule registers the plural rule of a language. The categories must be in the same order as the
+// msgstr[n] entries in the PO files of the language. The built-in rules covers most European and Asian languages.
+// It's safe to call this while other goroutines are rendering
+func RegisterPluralRule(lang string, rule PluralRule, categories ...Plural) {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+	rules[lang] = pluralRules{rule: rule, categories: categories}
+}
+
+// pluralRulesOf returns the plural rules of the supplied locale. Languages without a rule only use Other
+func pluralRulesOf(locale string) pluralRules {
+	rulesMutex.RLock()
+	defer rulesMutex.RUnlock()
+	if r, ok := rules[language(locale)]; ok {
+		return r
+	}
+	return pluralRules{
+		rule:       func(int) Plural { return Other },
+		categories: []Plural{Other},
+	}
+}
+
+// PluralOf returns the plural category of a count in the supplied locale
+func PluralOf(locale string, n int) Plural {
+	return pluralRulesOf(locale).rule(n)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func init() {
+	// one: i = 1 and v = 0
+	oneOther := func(n int) Plural {
+		if abs(n) == 1 {
+			return One
+		}
+		return Other
+	}
+	for _, lang := range []string{"en", "de", "nl", "sv", "da", "nb", "nn", "no", "fi", "et", "it", "es", "ca", "el",
+		"hu", "tr", "bg", "af", "sq", "eu", "gl", "ka", "kk", "ky", "mn", "sw", "ur", "uz", "az", "fy", "lb", "ta", "te"} {
+		RegisterPluralRule(lang, oneOther, One, Other)
+	}
+
+	// one: i = 0,1
+	for _, lang := range []string{"fr", "pt", "hy", "hi", "bn", "fa", "zu", "am"} {
+		RegisterPluralRule(lang, func(n int) Plural {
+			if abs(n) <= 1 {
+				return One
+			}
+			return Other
+		}, One, Other)
+	}
+
+	// only other
+	for _, lang := range []string{"ja", "zh", "ko", "vi", "th", "id", "ms", "my", "lo", "km", "yue"} {
+		RegisterPluralRule(lang, func(int) Plural { return Other }, Other)
+	}
+
+	// one: i % 10 = 1 and i % 100 != 11
+	// few: i % 10 = 2..4 and i % 100 != 12..14
+	// many: i % 10 = 0 or i % 10 = 5..9 or i % 100 = 11..14
+	eastSlavic := func(n int) Plural {
+		n = abs(n)
+		i10, i100 := n%10, n%100
+		switch {
+		case i10 == 1 && i100 != 11:
+			return One
+		case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+			return Few
+		}
+		return Many
+	}
+	for _, lang := range []string{"ru", "uk", "be"} {
+		RegisterPluralRule(lang, eastSlavic, One, Few, Many, Other)
+	}
+
+	// one: i = 1
+	// few: i % 10 = 2..4 and i % 100 != 12..14
+	// many: everything else
+	RegisterPluralRule("pl", func(n int) Plural {
+		n = abs(n)
+		i10, i100 := n%10, n%100
+		switch {
+		case n == 1:
+			return One
+		case i10 >= 2 && i10 <= 4 && (i100 < 12 || i100 > 14):
+			return Few
+		}
+		return Many
+	}, One, Few, Many, Other)
+
+	// one: i = 1
+	// few: i = 2..4
+	// many is only used for decimals, which means that PO files only have three forms
+	for _, lang := range []string{"cs", "sk"} {
+		RegisterPluralRule(lang, func(n int) Plural {
+			switch abs(n) {
+			case 1:
+				return One
+			case 2, 3, 4:
+				return Few
+			}
+			return Other
+		}, One, Few, Other)
+	}
+
+	// one: i = 1
+	// two: i = 2
+	RegisterPluralRule("he", func(n int) Plural {
+		switch abs(n) {
+		case 1:
+			return One
+		case 2:
+			return Two
+		}
+		return Other
+	}, One, Two, Other)
+
+	// zero: n = 0
+	// one: n = 1
+	// two: n = 2
+	// few: n % 100 = 3..10
+	// many: n % 100 = 11..99
+	RegisterPluralRule("ar", func(n int) Plural {
+		n = abs(n)
+		n100 := n % 100
+		switch {
+		case n == 0:
+			return Zero
+		case n == 1:
+			return One
+		case n == 2:
+			return Two
+		case n100 >= 3 && n100 <= 10:
+			return Few
+		case n100 >= 11:
+			return Many
+		}
+		return Other
+	}, Zero, One, Two, Few, Many, Other)
+}
@@ -0,0 +1,182 @@
+package img
+
+import (
+	"errors"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Format is the mime type of an image
+type Format string
+
+const (
+	AVIF Format = "image/avif"
+	WebP Format = "image/webp"
+	JPEG Format = "image/jpeg"
+	PNG  Format = "image/png"
+	GIF  Format = "image/gif"
+)
+
+// modern contains formats that not all browsers support, in the order they are preferred. All other formats are
+// considered to be supported by every browser and can be used by the img tag
+var modern = []Format{AVIF, WebP}
+
+var (
+	// ErrMissingAlt is the error when an image has no alt text and isn't marked as decorative
+	ErrMissingAlt = errors.New("img: alt text is required, set Decorative if the image is decorative")
+	// ErrNoVariants is the error when an image has no variants
+	ErrNoVariants = errors.New("img: at least one variant is required")
+)
+
+// Variant is a single file of an image
+type Variant struct {
+	URL    string
+	Format Format
+	// Width and Height, in pixels, of the file
+	Width  int
+	Height int
+}
+
+// Image describes an image that is available in multiple sizes and formats
+//
+// Example:
+//
+//	img.Picture(img.Image{
+//	  Alt: "A cat sleeping in the sun",
+//	  Sizes: "(max-width: 600px) 100vw, 50vw",
+//	  Variants: []img.Variant{
+//	    {URL: "/img/cat-480.avif", Format: img.AVIF, Width: 480, Height: 320},
+//	    {URL: "/img/cat-960.avif", Format: img.AVIF, Width: 960, Height: 640},
+//	    {URL: "/img/cat-480.jpg", Format: img.JPEG, Width: 480, Height: 320},
+//	    {URL: "/img/cat-960.jpg", Format: img.JPEG, Width: 960, Height: 640},
+//	  },
+//	})
+type Image struct {
+	// Alt is the text that describes the image. Required unless the image is decorative
+	Alt string
+	// Decorative images get an empty alt text, which tells screen readers to ignore them
+	Decorative bool
+	// Variants of the image
+	Variants []Variant
+	// Sizes tells the browser how wide the image is rendered, for example "(max-width: 600px) 100vw, 50vw"
+	Sizes string
+	// Eager disables lazy loading. Use it for images that are visible when the page is loaded
+	Eager bool
+}
+
+// escapeURL makes sure that the URL doesn't break the comma- and space separated srcset list
+func escapeURL(url string) string {
+	return strings.NewReplacer(" ", "%20", ",", "%2C").Replace(url)
+}
+
+// srcSet creates the value of a srcset attribute
+func srcSet(variants []Variant) string {
+	sb := strings.Builder{}
+	for i, v := range variants {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(escapeURL(v.URL))
+		if v.Width > 0 {
+			sb.WriteByte(' ')
+			sb.WriteString(strconv.Itoa(v.Width))
+			sb.WriteByte('w')
+		}
+	}
+	return html.EscapeString(sb.String())
+}
+
+// isModern returns true if not all browsers support the format
+func isModern(f Format) bool {
+	for _, m := range modern {
+		if f == m {
+			return true
+		}
+	}
+	return false
+}
+
+// sources emits a source tag for each modern format and returns the variants that are used by the img tag. The
+// variants of the last format are used by the img tag if all variants use a modern format
+func (i Image) sources() ([]h.Node, []Variant) {
+	byFormat := map[Format][]Variant{}
+	var fallback []Variant
+	for _, v := range i.Variants {
+		if isModern(v.Format) {
+			byFormat[v.Format] = append(byFormat[v.Format], v)
+		} else {
+			fallback = append(fallback, v)
+		}
+	}
+
+	var result []h.Node
+	for n, f := range modern {
+		variants, ok := byFormat[f]
+		if !ok {
+			continue
+		}
+		if fallback == nil && !hasAnyAfter(byFormat, n) {
+			fallback = variants
+			break
+		}
+		attrs := []h.Node{a.Type(string(f)), a.SrcSet(srcSet(variants))}
+		if i.Sizes != "" {
+			attrs = append(attrs, a.Sizes(html.EscapeString(i.Sizes)))
+		}
+		result = append(result, h.Source(attrs...))
+	}
+	return result, fallback
+}
+
+// hasAnyAfter returns true if there are variants of a modern format after the supplied index
+func hasAnyAfter(byFormat map[Format][]Variant, n int) bool {
+	for _, f := range modern[n+1:] {
+		if _, ok := byFormat[f]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Picture emits a picture tag with a source tag for each modern format and an img tag for the formats that
+// all browsers support. The width and height of the img tag are taken from the largest variant, so that the browser
+// can reserve space for the image before it's loaded. The supplied nodes are added to the img tag.
+//
+// The rendering fails with ErrMissingAlt if the image doesn't have an alt text and isn't decorative
+func Picture(i Image, c ...h.Node) h.Node {
+	if i.Alt == "" && !i.Decorative {
+		return h.Fail(ErrMissingAlt)
+	}
+	if len(i.Variants) == 0 {
+		return h.Fail(ErrNoVariants)
+	}
+
+	sources, fallback := i.sources()
+	largest := fallback[0]
+	for _, v := range fallback[1:] {
+		if v.Width > largest.Width {
+			largest = v
+		}
+	}
+
+	attrs := []h.Node{a.Src(largest.URL)}
+	if len(fallback) > 1 {
+		attrs = append(attrs, a.SrcSet(srcSet(fallback)))
+		if i.Sizes != "" {
+			attrs = append(attrs, a.Sizes(html.EscapeString(i.Sizes)))
+		}
+	}
+	attrs = append(attrs, a.Alt(html.EscapeString(i.Alt)))
+	if largest.Width > 0 && largest.Height > 0 {
+		attrs = append(attrs, a.Width(largest.Width), a.Height(largest.Height))
+	}
+	if !i.Eager {
+		attrs = append(attrs, a.Loading(a.LoadingLazy))
+	}
+	attrs = append(attrs, a.Attrib("decoding", "async"))
+
+	return h.Picture(append(sources, h.Img(append(attrs, c...)...))...)
+}
@@ -0,0 +1,85 @@
+package live
+
+// ClientScript is the script that connects each live view in the page to the server and applies the patches it
+// receives. Elements inside a live view can send events to the server using the following attributes:
+//
+//   - live-click: sends the event when the element is clicked
+//   - live-change: sends the event, with the name and value of the element, when the value is changed
+//   - live-submit: sends the event, with all values in the form, when the form is submitted
+//   - live-value: an extra value sent together with the event
+const ClientScript = `(function () {
+  function connect(root) {
+    var proto = location.protocol === "https:" ? "wss:" : "ws:";
+    var ws = new WebSocket(proto + "//" + location.host + root.getAttribute("data-live"));
+    function find(path) {
+      var n = root;
+      for (var i = 0; i < path.length && n; i++) n = n.childNodes[path[i]];
+      return n;
+    }
+    function frag(html) {
+      var t = document.createElement("template");
+      t.innerHTML = html;
+      return t.content;
+    }
+    function apply(p) {
+      var n = find(p.path || []);
+      if (!n) return;
+      switch (p.op) {
+      case "replace":
+        if (n === root) root.innerHTML = p.html; else n.parentNode.replaceChild(frag(p.html), n);
+        break;
+      case "text":
+        n.nodeValue = p.text;
+        break;
+      case "attrs":
+        for (var k in p.set || {}) {
+          n.setAttribute(k, p.set[k]);
+          if (k === "value" && "value" in n) n.value = p.set[k];
+          if (k === "checked") n.checked = true;
+        }
+        (p.remove || []).forEach(function (k) {
+          n.removeAttribute(k);
+          if (k === "checked") n.checked = false;
+        });
+        break;
+      case "append":
+        n.appendChild(frag(p.html));
+        break;
+      case "remove":
+        n.parentNode.removeChild(n);
+        break;
+      }
+    }
+    ws.onmessage = function (m) {
+      JSON.parse(m.data).forEach(apply);
+    };
+    ws.onclose = function () {
+      setTimeout(function () { connect(root); }, 1000);
+    };
+    function send(el, attr, value, form) {
+      if (ws.readyState !== 1) return;
+      ws.send(JSON.stringify({event: el.getAttribute(attr), value: value || el.getAttribute("live-value") || "", form: form || {}}));
+    }
+    function closest(e, attr) {
+      var el = e.target.closest && e.target.closest("[" + attr + "]");
+      return el && root.contains(el) ? el : null;
+    }
+    root.addEventListener("click", function (e) {
+      var el = closest(e, "live-click");
+      if (el) { e.preventDefault(); send(el, "live-click"); }
+    });
+    root.addEventListener("change", function (e) {
+      var el = closest(e, "live-change");
+      if (el) { var f = {}; if (e.target.name) f[e.target.name] = e.target.value; send(el, "live-change", e.target.value, f); }
+    });
+    root.addEventListener("submit", function (e) {
+      var el = closest(e, "live-submit");
+      if (!el) return;
+      e.preventDefault();
+      var f = {};
+      new FormData(e.target).forEach(function (v, k) { f[k] = String(v); });
+      send(el, "live-submit", "", f);
+    });
+  }
+  document.querySelectorAll("[data-live]").forEach(connect);
+})();`
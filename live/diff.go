@@ -0,0 +1,164 @@
+package live
+
+import (
+	"github.com/westcoastcode-se/gohtml/dom"
+	"strings"
+)
+
+// Op is the type of a patch operation
+type Op string
+
+const (
+	// OpReplace replaces the node at the path with the supplied HTML. An empty path replaces the content of the root
+	OpReplace Op = "replace"
+	// OpText changes the text of the text node, or comment, at the path
+	OpText Op = "text"
+	// OpAttrs sets and removes attributes on the element at the path
+	OpAttrs Op = "attrs"
+	// OpAppend appends the supplied HTML to the element at the path
+	OpAppend Op = "append"
+	// OpRemove removes the node at the path
+	OpRemove Op = "remove"
+)
+
+// Patch is a single operation that the client applies to the DOM. The path is a list of child-node indices, starting
+// from the root element of the live view
+type Patch struct {
+	Op     Op                `json:"op"`
+	Path   []int             `json:"path"`
+	HTML   string            `json:"html,omitempty"`
+	Text   string            `json:"text,omitempty"`
+	Set    map[string]string `json:"set,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// Diff returns the operations needed to turn the old children of the root into the new children. Nodes are compared
+// by position, which means that the patches are small when content is changed in place, but large when
+// nodes are inserted at the beginning of a list.
+//
+// The paths are indices in the trees built by dom.Parse. They only point to the right nodes in the browser if it
+// builds the same tree from the HTML, which isn't the case for a table without a tbody or a div inside a p, for
+// example. The session replaces the whole view instead of sending the patches when that happens
+func Diff(old, new []*dom.Node) []Patch {
+	var patches []Patch
+	diffChildren(nil, old, new, &patches)
+	return patches
+}
+
+// path creates a new path with the supplied index added
+func path(parent []int, i int) []int {
+	p := make([]int, len(parent)+1)
+	copy(p, parent)
+	p[len(parent)] = i
+	return p
+}
+
+func html(nodes ...*dom.Node) string {
+	sb := strings.Builder{}
+	for _, n := range nodes {
+		_, _ = n.WriteTo(&sb)
+	}
+	return sb.String()
+}
+
+func diffChildren(parent []int, old, new []*dom.Node, patches *[]Patch) {
+	n := min(len(old), len(new))
+	for i := 0; i < n; i++ {
+		diffNode(path(parent, i), old[i], new[i], patches)
+	}
+	if len(new) > n {
+		*patches = append(*patches, Patch{Op: OpAppend, Path: parent, HTML: html(new[n:]...)})
+	}
+	// remove from the end so that the indices of the remaining nodes are unchanged
+	for i := len(old) - 1; i >= n; i-- {
+		*patches = append(*patches, Patch{Op: OpRemove, Path: path(parent, i)})
+	}
+}
+
+func diffNode(p []int, old, new *dom.Node, patches *[]Patch) {
+	if old.Type != new.Type || old.Tag != new.Tag {
+		*patches = append(*patches, Patch{Op: OpReplace, Path: p, HTML: new.HTML()})
+		return
+	}
+	switch new.Type {
+	case dom.TextNode, dom.CommentNode:
+		if old.Data != new.Data {
+			*patches = append(*patches, Patch{Op: OpText, Path: p, Text: new.Data})
+		}
+		return
+	case dom.DoctypeNode:
+		return
+	}
+
+	// the content of raw text elements is replaced as a whole
+	if dom.IsRawText(new.Tag) && html(old.Children...) != html(new.Children...) {
+		*patches = append(*patches, Patch{Op: OpReplace, Path: p, HTML: new.HTML()})
+		return
+	}
+
+	attrs := Patch{Op: OpAttrs, Path: p}
+	for _, a := range new.Attrs {
+		if v, ok := old.Attr(a.Key); !ok || v != a.Value {
+			if attrs.Set == nil {
+				attrs.Set = make(map[string]string)
+			}
+			attrs.Set[a.Key] = a.Value
+		}
+	}
+	for _, a := range old.Attrs {
+		if _, ok := new.Attr(a.Key); !ok {
+			attrs.Remove = append(attrs.Remove, a.Key)
+		}
+	}
+	if attrs.Set != nil || attrs.Remove != nil {
+		*patches = append(*patches, attrs)
+	}
+
+	diffChildren(p, old.Children, new.Children, patches)
+}
+
+// blockElements are the elements that implicitly close an open p element in the browser
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "details": true, "div": true, "dl": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hgroup": true, "hr": true, "main": true,
+	"menu": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true, "table": true, "ul": true,
+}
+
+// tableChildren are the elements allowed as children of the table elements. The browser moves, or wraps, all other
+// content which changes the indices of the child-nodes
+var tableChildren = map[string]map[string]bool{
+	"table": {"caption": true, "colgroup": true, "thead": true, "tbody": true, "tfoot": true},
+	"thead": {"tr": true},
+	"tbody": {"tr": true},
+	"tfoot": {"tr": true},
+	"tr":    {"td": true, "th": true},
+}
+
+// sameAsBrowser returns false if the browser builds a different tree than dom.Parse from the HTML of the nodes
+func sameAsBrowser(nodes []*dom.Node) bool {
+	for _, n := range nodes {
+		if n.Type != dom.ElementNode {
+			continue
+		}
+		// the content of a template isn't part of its child-nodes in the browser
+		if n.Tag == "template" {
+			return false
+		}
+		allowed, table := tableChildren[n.Tag]
+		for _, c := range n.Children {
+			switch {
+			case n.Tag == "p" && c.Type == dom.ElementNode && blockElements[c.Tag]:
+				return false
+			case table && c.Type == dom.ElementNode && !allowed[c.Tag] && c.Tag != "script" && c.Tag != "style":
+				return false
+			case table && c.Type == dom.TextNode && strings.TrimSpace(c.Data) != "":
+				return false
+			}
+		}
+		if !sameAsBrowser(n.Children) {
+			return false
+		}
+	}
+	return true
+}
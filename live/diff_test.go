@@ -0,0 +1,66 @@
+package live
+
+import (
+	"github.com/westcoastcode-se/gohtml/dom"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected []Patch
+	}{
+		{"unchanged", `<p class="a">x</p>`, `<p class="a">x</p>`, nil},
+		{"text", `<p>x</p><p>y</p>`, `<p>x</p><p>z</p>`, []Patch{{Op: OpText, Path: []int{1, 0}, Text: "z"}}},
+		{"attributes", `<p class="a" id="b">x</p>`, `<p class="c" title="d">x</p>`, []Patch{
+			{Op: OpAttrs, Path: []int{0}, Set: map[string]string{"class": "c", "title": "d"}, Remove: []string{"id"}},
+		}},
+		{"tag", `<p>x</p>`, `<div>x</div>`, []Patch{{Op: OpReplace, Path: []int{0}, HTML: `<div>x</div>`}}},
+		{"append", `<ul><li>a</li></ul>`, `<ul><li>a</li><li>b</li><li>c</li></ul>`, []Patch{
+			{Op: OpAppend, Path: []int{0}, HTML: `<li>b</li><li>c</li>`},
+		}},
+		{"remove", `<ul><li>a</li><li>b</li><li>c</li></ul>`, `<ul><li>a</li></ul>`, []Patch{
+			{Op: OpRemove, Path: []int{0, 2}},
+			{Op: OpRemove, Path: []int{0, 1}},
+		}},
+		{"raw text", `<script>a()</script>`, `<script>b()</script>`, []Patch{
+			{Op: OpReplace, Path: []int{0}, HTML: `<script>b()</script>`},
+		}},
+		{"append to root", `<p>x</p>`, `<p>x</p>y`, []Patch{{Op: OpAppend, HTML: `y`}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := Diff(dom.Parse(test.old), dom.Parse(test.new))
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Errorf("expected %+v but was %+v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSameAsBrowser(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected bool
+	}{
+		{"plain", `<div><p>x</p><ul><li>a</li></ul></div>`, true},
+		{"table", "<table>\n<thead><tr><th>a</th></tr></thead><tbody><tr><td>b</td></tr></tbody></table>", true},
+		{"table without tbody", `<table><tr><td>a</td></tr></table>`, false},
+		{"text in table", `<table>x<tbody></tbody></table>`, false},
+		{"td in tbody", `<table><tbody><td>a</td></tbody></table>`, false},
+		{"div in p", `<p><div>x</div></p>`, false},
+		{"span in p", `<p><span>x</span></p>`, true},
+		{"template", `<div><template><p>x</p></template></div>`, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := sameAsBrowser(dom.Parse(test.html)); actual != test.expected {
+				t.Errorf("expected %v but was %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package live
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/dom"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"sync"
+)
+
+// Event is sent from the browser when the user interacts with an element that has a live-click,
+// live-change or live-submit attribute
+type Event struct {
+	// Name is the value of the attribute that triggered the event
+	Name string `json:"event"`
+	// Value is the value of the live-value attribute or the value of the changed input field
+	Value string `json:"value"`
+	// Form contains the submitted values of a form, or the name and value of the changed input field
+	Form map[string]string `json:"form"`
+}
+
+// View is a component that is rendered on the server and kept up to date in the browser. The view is rendered again,
+// and the changes are sent to the browser, every time an event has been handled
+type View interface {
+	h.Component
+	// HandleEvent is called when the user triggers an event in the browser. The view is rendered again after the
+	// event has been handled. Changes made using Session.Update while the event is handled are applied before that
+	HandleEvent(s *Session, e Event)
+}
+
+// Mounter can be implemented by views that want to know when a browser has connected. This is where you
+// start goroutines that update the view when something happens on the server. Such goroutines are expected
+// to change the view using Session.Update and to stop when the context of the session is done
+type Mounter interface {
+	Mount(s *Session)
+}
+
+// Session is a single view connected to a browser
+type Session struct {
+	ctx  context.Context
+	conn *Conn
+	view View
+	lock sync.Mutex
+	tree []*dom.Node
+	// queue protects applying and pending
+	queue sync.Mutex
+	// applying is true while the changes of an update, or an event, are being applied
+	applying bool
+	// pending are changes supplied while another update was applied
+	pending []func()
+}
+
+// Context returns the context of the session. The context is done when the browser disconnects
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// View returns the view of this session
+func (s *Session) View() View {
+	return s.view
+}
+
+// Update applies the supplied changes to the view, renders it and sends the changes to the browser. It's safe to
+// call this from any goroutine. The changes, the events handled by the view and the rendering are serialized by the
+// session, which means that the view doesn't need a lock of its own as long as it's only changed this way.
+//
+// Calling Update while other changes are applied, for example from View.HandleEvent, never blocks. The changes are
+// queued and applied by the goroutine that applies the other changes, before the view is rendered. Update returns
+// nil without waiting for the changes in that case
+//
+// Example:
+//
+//	_ = s.Update(func() {
+//	  c.now = t
+//	})
+func (s *Session) Update(changes ...func()) error {
+	if !s.lock.TryLock() {
+		s.queue.Lock()
+		if s.applying {
+			s.pending = append(s.pending, changes...)
+			s.queue.Unlock()
+			return nil
+		}
+		s.queue.Unlock()
+		s.lock.Lock()
+	}
+	defer s.lock.Unlock()
+	s.apply(changes)
+	return s.render()
+}
+
+// apply applies the supplied changes and all changes queued while doing so. The session must be locked
+func (s *Session) apply(changes []func()) {
+	s.queue.Lock()
+	s.applying = true
+	s.queue.Unlock()
+	for {
+		for _, change := range changes {
+			change()
+		}
+		s.queue.Lock()
+		changes, s.pending = s.pending, nil
+		if len(changes) == 0 {
+			s.applying = false
+			s.queue.Unlock()
+			return
+		}
+		s.queue.Unlock()
+	}
+}
+
+// handleEvent lets the view handle the event and sends the changes to the browser
+func (s *Session) handleEvent(e Event) error {
+	return s.Update(func() {
+		s.view.HandleEvent(s, e)
+	})
+}
+
+// render renders the view and sends the changes to the browser. The session must be locked
+func (s *Session) render() error {
+	bb := bytes.Buffer{}
+	if _, err := h.Fragment(s.view.Render())(h.WithContext(s.ctx, &bb)); err != nil {
+		return err
+	}
+	tree := dom.Parse(bb.String())
+	var patches []Patch
+	if s.tree == nil || !sameAsBrowser(s.tree) || !sameAsBrowser(tree) {
+		// the paths in the patches can't be trusted if the browser builds a different tree from the HTML
+		patches = []Patch{{Op: OpReplace, Path: []int{}, HTML: bb.String()}}
+	} else {
+		patches = Diff(s.tree, tree)
+	}
+	s.tree = tree
+	if len(patches) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(patches)
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(data)
+}
+
+// Live serves a live view. The view is rendered as part of a normal page using Node and is then kept up to date
+// using a WebSocket connection to the url of the Live handler
+//
+// Example:
+//
+//	var counter = live.New("/live/counter", func(r *http.Request) live.View {
+//	  return &Counter{}
+//	})
+//
+//	func index(w http.ResponseWriter, r *http.Request) {
+//	  _, _ = h.Html(
+//	    h.Head(),
+//	    h.Body(counter.Node(r)),
+//	  )(h.WithContext(r.Context(), w))
+//	}
+//
+//	func main() {
+//	  http.Handle("/live/counter", counter)
+//	  http.HandleFunc("/", index)
+//	}
+type Live struct {
+	url   string
+	mount func(r *http.Request) View
+}
+
+// New creates a new live view served from the supplied url. The mount function is called once when the page is
+// rendered and once more when the browser connects
+func New(url string, mount func(r *http.Request) View) *Live {
+	return &Live{
+		url:   url,
+		mount: mount,
+	}
+}
+
+// Node renders the view inside the element that the client script connects to the server. The client script is
+// registered using h.UseHead
+func (l *Live) Node(r *http.Request) h.Node {
+	return h.Join(
+		h.UseHead("live:client", h.Script(h.Text(ClientScript))),
+		h.Div(a.Attrib("data-live", l.url),
+			h.Render(l.mount(r)),
+		),
+	)
+}
+
+// ServeHTTP accepts the WebSocket connection from the browser
+func (l *Live) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	s := &Session{
+		ctx:  ctx,
+		conn: conn,
+		view: l.mount(r),
+	}
+	if err := s.Update(); err != nil {
+		return
+	}
+	if m, ok := s.view.(Mounter); ok {
+		m.Mount(s)
+	}
+	for {
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		if err := s.handleEvent(e); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package live
+
+import (
+	"encoding/json"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type counter struct {
+	count int
+	rows  int
+}
+
+func (c *counter) Render() h.Node {
+	return h.Div(
+		h.P(h.Textf("Count: %d", c.count)),
+		h.Button(a.Attrib("live-click", "inc"), h.Text("+1")),
+		h.EmitIf(c.rows > 0, func() h.Node {
+			rows := make([]h.Node, c.rows)
+			for i := range rows {
+				rows[i] = h.Tr(h.Td(h.Textf("%d", i)))
+			}
+			return h.Table(rows...)
+		}),
+	)
+}
+
+func (c *counter) HandleEvent(s *Session, e Event) {
+	switch e.Name {
+	case "inc":
+		c.count++
+	case "twice":
+		// updates while handling an event are applied before the view is rendered
+		_ = s.Update(func() {
+			c.count += 2
+		})
+	case "rows":
+		c.rows++
+	}
+}
+
+// connect starts a live view and returns the connection together with the first message
+func connect(t *testing.T) *Conn {
+	server := httptest.NewServer(New("/", func(r *http.Request) View {
+		return &counter{}
+	}))
+	t.Cleanup(server.Close)
+	conn, err := Dial(wsURL(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+func send(t *testing.T, conn *Conn, event string) []Patch {
+	if event != "" {
+		data, _ := json.Marshal(Event{Name: event})
+		if err := conn.WriteMessage(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var patches []Patch
+	if err := json.Unmarshal(data, &patches); err != nil {
+		t.Fatal(err)
+	}
+	return patches
+}
+
+func TestEvents(t *testing.T) {
+	conn := connect(t)
+	expected := []Patch{{Op: OpReplace, Path: []int{},
+		HTML: `<div><p>Count: 0</p><button live-click="inc">+1</button></div>`}}
+	if patches := send(t, conn, ""); !reflect.DeepEqual(patches, expected) {
+		t.Errorf("expected %+v but was %+v", expected, patches)
+	}
+	expected = []Patch{{Op: OpText, Path: []int{0, 0, 0}, Text: "Count: 1"}}
+	if patches := send(t, conn, "inc"); !reflect.DeepEqual(patches, expected) {
+		t.Errorf("expected %+v but was %+v", expected, patches)
+	}
+}
+
+func TestUpdateWhileHandlingEvent(t *testing.T) {
+	conn := connect(t)
+	send(t, conn, "")
+	expected := []Patch{{Op: OpText, Path: []int{0, 0, 0}, Text: "Count: 2"}}
+	if patches := send(t, conn, "twice"); !reflect.DeepEqual(patches, expected) {
+		t.Errorf("expected %+v but was %+v", expected, patches)
+	}
+}
+
+func TestReplaceWhenBrowserTreeDiffers(t *testing.T) {
+	conn := connect(t)
+	send(t, conn, "")
+	// the browser adds a tbody to the table, which means that the paths from Diff would be wrong
+	patches := send(t, conn, "rows")
+	if len(patches) != 1 || patches[0].Op != OpReplace || len(patches[0].Path) != 0 {
+		t.Errorf("expected the whole view to be replaced but was %+v", patches)
+	}
+}
@@ -0,0 +1,313 @@
+package live
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// This file contains a minimal WebSocket implementation (RFC 6455). It only supports what's needed by
+// the live views: text messages, ping/pong and closing the connection
+
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MaxMessageSize is the largest message that is accepted from the other side of the connection
+const MaxMessageSize = 1 << 20
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+var (
+	// ErrNotWebSocket is returned when trying to upgrade a request that isn't a WebSocket handshake
+	ErrNotWebSocket = errors.New("not a websocket handshake")
+	// ErrBadOrigin is returned when the origin of the request isn't allowed
+	ErrBadOrigin = errors.New("origin not allowed")
+	// ErrMessageTooLarge is returned if the other side sends a message larger than MaxMessageSize
+	ErrMessageTooLarge = errors.New("message too large")
+	// ErrProtocol is returned if the other side sends a frame that isn't allowed by RFC 6455. The connection is
+	// closed when this happens
+	ErrProtocol = errors.New("websocket protocol error")
+)
+
+// Conn is a WebSocket connection
+type Conn struct {
+	conn   net.Conn
+	rw     *bufio.ReadWriter
+	client bool
+	lock   sync.Mutex
+}
+
+// acceptKey computes the value of the Sec-WebSocket-Accept header
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// sameOrigin returns true if the origin of the request, if any, is the same host as the request
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+func headerContains(r *http.Request, key, value string) bool {
+	for _, v := range strings.Split(r.Header.Get(key), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Upgrade upgrades the supplied request into a WebSocket connection. Requests from other origins are rejected
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet || !headerContains(r, "Connection", "upgrade") ||
+		!headerContains(r, "Upgrade", "websocket") || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		http.Error(w, ErrNotWebSocket.Error(), http.StatusBadRequest)
+		return nil, ErrNotWebSocket
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, ErrNotWebSocket.Error(), http.StatusBadRequest)
+		return nil, ErrNotWebSocket
+	}
+	if !sameOrigin(r) {
+		http.Error(w, ErrBadOrigin.Error(), http.StatusForbidden)
+		return nil, ErrBadOrigin
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return nil, err
+	}
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+	_, _ = rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &Conn{conn: conn, rw: rw}, nil
+}
+
+// Dial opens a WebSocket connection to the supplied ws:// url. This is mostly useful when testing live views
+// using an in-process server, such as httptest.Server
+func Dial(rawURL string) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://"+u.Host+u.RequestURI(), nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	resp, err := http.ReadResponse(rw.Reader, req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		_ = conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: %s", resp.Status)
+	}
+	return &Conn{conn: conn, rw: rw, client: true}, nil
+}
+
+// Close closes the connection
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}
+
+// WriteMessage writes a text message
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+func (c *Conn) writeFrame(op byte, data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | op
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch l := len(data); {
+	case l < 126:
+		header[1] = maskBit | byte(l)
+	case l <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = binary.BigEndian.AppendUint16(header, uint16(l))
+	default:
+		header[1] = maskBit | 127
+		header = binary.BigEndian.AppendUint64(header, uint64(l))
+	}
+	if c.client {
+		mask := make([]byte, 4)
+		_, _ = rand.Read(mask)
+		header = append(header, mask...)
+		masked := make([]byte, len(data))
+		for i := range data {
+			masked[i] = data[i] ^ mask[i%4]
+		}
+		data = masked
+	}
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	if _, err := c.rw.Write(data); err != nil {
+		return err
+	}
+	return c.rw.Flush()
+}
+
+// ReadMessage reads the next text or binary message. Ping and close frames are handled automatically.
+// Returns io.EOF if the other side closed the connection
+func (c *Conn) ReadMessage() ([]byte, error) {
+	message, err := c.readMessage()
+	if errors.Is(err, ErrProtocol) {
+		// status code 1002: protocol error
+		_ = c.writeFrame(opClose, []byte{0x03, 0xEA})
+		_ = c.conn.Close()
+	}
+	return message, err
+}
+
+func (c *Conn) readMessage() ([]byte, error) {
+	var message []byte
+	fragmented := false
+	for {
+		fin, op, data, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, data); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			_ = c.writeFrame(opClose, nil)
+			_ = c.conn.Close()
+			return nil, io.EOF
+		case opContinuation:
+			if !fragmented {
+				return nil, fmt.Errorf("%w: unexpected continuation frame", ErrProtocol)
+			}
+		case opText, opBinary:
+			if fragmented {
+				return nil, fmt.Errorf("%w: expected a continuation frame", ErrProtocol)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown opcode %d", ErrProtocol, op)
+		}
+		if len(message)+len(data) > MaxMessageSize {
+			return nil, ErrMessageTooLarge
+		}
+		message = append(message, data...)
+		if fin {
+			return message, nil
+		}
+		fragmented = true
+	}
+}
+
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.rw, header); err != nil {
+		return false, 0, nil, err
+	}
+	fin := header[0]&0x80 != 0
+	op := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+	if header[0]&0x70 != 0 {
+		// no extensions are negotiated, which means that the reserved bits must be zero
+		return false, 0, nil, fmt.Errorf("%w: reserved bits are set", ErrProtocol)
+	}
+	if masked == c.client {
+		// frames from the client must be masked and frames from the server must not
+		return false, 0, nil, fmt.Errorf("%w: invalid masking", ErrProtocol)
+	}
+	if op&0x08 != 0 && (!fin || length > 125) {
+		return false, 0, nil, fmt.Errorf("%w: fragmented or too large control frame", ErrProtocol)
+	}
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.rw, ext); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+	var mask []byte
+	if masked {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(c.rw, mask); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(c.rw, data); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range data {
+			data[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, data, nil
+}
@@ -0,0 +1,126 @@
+package live
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// echo returns a server that sends back all messages it receives. Errors from ReadMessage are sent to the channel
+func echo(t *testing.T, errs chan<- error) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			data, err := conn.ReadMessage()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := conn.WriteMessage(data); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestHandshake(t *testing.T) {
+	errs := make(chan error, 1)
+	server := echo(t, errs)
+	conn, err := Dial(wsURL(server))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	long := strings.Repeat("x", 70000)
+	for _, message := range []string{"hello", strings.Repeat("x", 200), long} {
+		if err := conn.WriteMessage([]byte(message)); err != nil {
+			t.Fatal(err)
+		}
+		data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != message {
+			t.Errorf("expected a message with %d bytes but was %d bytes", len(message), len(data))
+		}
+	}
+}
+
+func TestUpgradeRejectsInvalidRequests(t *testing.T) {
+	errs := make(chan error, 1)
+	server := echo(t, errs)
+	tests := []struct {
+		name     string
+		header   map[string]string
+		expected int
+	}{
+		{"not a websocket", map[string]string{}, http.StatusBadRequest},
+		{"missing key", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13"},
+			http.StatusBadRequest},
+		{"other origin", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13",
+			"Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ==", "Origin": "https://example.com"}, http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			for k, v := range test.header {
+				req.Header.Set(k, v)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != test.expected {
+				t.Errorf("expected %d but was %d", test.expected, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame []byte
+	}{
+		{"unmasked", []byte{0x81, 0x01, 'x'}},
+		{"reserved bits", []byte{0xC1, 0x81, 0, 0, 0, 0, 'x'}},
+		{"fragmented ping", []byte{0x09, 0x80, 0, 0, 0, 0}},
+		{"unexpected continuation", []byte{0x80, 0x81, 0, 0, 0, 0, 'x'}},
+		{"unknown opcode", []byte{0x83, 0x80, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errs := make(chan error, 1)
+			server := echo(t, errs)
+			conn, err := Dial(wsURL(server))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if _, err := conn.conn.Write(test.frame); err != nil {
+				t.Fatal(err)
+			}
+			if err := <-errs; !errors.Is(err, ErrProtocol) {
+				t.Errorf("expected %v but was %v", ErrProtocol, err)
+			}
+			// the server closes the connection
+			if _, err := conn.ReadMessage(); err != io.EOF {
+				t.Errorf("expected %v but was %v", io.EOF, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockCode
+	blockQuote
+	blockList
+	blockItem
+	blockBreak
+	blockTable
+)
+
+// block is a single block in the document
+type block struct {
+	kind blockKind
+	// text is the raw content of a paragraph or heading, or the content of a code block
+	text string
+	// level of a heading
+	level int
+	// lang is the info string of a fenced code block
+	lang     string
+	children []*block
+	// ordered, start and tight are used by lists
+	ordered bool
+	start   int
+	tight   bool
+	// align, header and rows are used by tables
+	align  []string
+	header []string
+	rows   [][]string
+}
+
+// reference is a link reference definition, such as [label]: /url "title"
+type reference struct {
+	href  string
+	title string
+}
+
+// parser contains the state of a document being parsed
+type parser struct {
+	refs map[string]reference
+}
+
+var (
+	atxHeadingPattern    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreakPattern = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	fencePattern         = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*?)[ \t]*$")
+	setextPattern        = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	bulletPattern        = regexp.MustCompile(`^( {0,3})([-+*])( {1,4}|[ \t]*$)`)
+	orderedPattern       = regexp.MustCompile(`^( {0,3})(\d{1,9})([.)])( {1,4}|[ \t]*$)`)
+	quotePattern         = regexp.MustCompile(`^ {0,3}> ?`)
+	delimiterRowPattern  = regexp.MustCompile(`^ {0,3}\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	referencePattern     = regexp.MustCompile(`^ {0,3}\[((?:[^\]\\]|\\.)+)\]:[ \t]*(<[^>\n]*>|\S+)(?:[ \t]+("(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'|\((?:[^)\\]|\\.)*\)))?[ \t]*$`)
+)
+
+// expandTabs replaces the tabs in the indentation of a line with spaces, using tab stops of 4 characters
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	sb := strings.Builder{}
+	col := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\t':
+			n := 4 - col%4
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+		case ' ', '>':
+			sb.WriteByte(line[i])
+			col++
+		default:
+			sb.WriteString(line[i:])
+			return sb.String()
+		}
+	}
+	return sb.String()
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// indentOf returns the number of spaces at the beginning of the line
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// stripIndent removes up to n spaces from the beginning of the line
+func stripIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return line[i:]
+}
+
+// normalizeLabel makes link labels case-insensitive and ignores differences in whitespace
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
+
+// listMarker is the marker of a list item
+type listMarker struct {
+	ordered bool
+	// char is the bullet, or the delimiter after the number of an ordered list
+	char  byte
+	start int
+	// width is the indentation of the content of the item
+	width int
+	empty bool
+}
+
+// parseListMarker returns the list marker at the beginning of the line
+func parseListMarker(line string) (listMarker, bool) {
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		return newListMarker(line, false, m[2][0], 0, len(m[1])+1, m[3]), true
+	}
+	if m := orderedPattern.FindStringSubmatch(line); m != nil {
+		start, _ := strconv.Atoi(m[2])
+		return newListMarker(line, true, m[3][0], start, len(m[1])+len(m[2])+1, m[4]), true
+	}
+	return listMarker{}, false
+}
+
+func newListMarker(line string, ordered bool, char byte, start int, end int, spaces string) listMarker {
+	m := listMarker{ordered: ordered, char: char, start: start, width: end + len(spaces)}
+	if isBlank(line[end:]) {
+		m.empty = true
+		m.width = end + 1
+	} else if len(spaces) > 4 {
+		// the content is an indented code block
+		m.width = end + 1
+	}
+	return m
+}
+
+// isListItem returns true if the line starts with a list marker
+func isListItem(line string) bool {
+	_, ok := parseListMarker(line)
+	return ok
+}
+
+// startsBlock returns true if the line starts a block that interrupts a paragraph
+func startsBlock(line string) bool {
+	if atxHeadingPattern.MatchString(line) || thematicBreakPattern.MatchString(line) ||
+		fencePattern.MatchString(line) || quotePattern.MatchString(line) {
+		return true
+	}
+	if m, ok := parseListMarker(line); ok {
+		return !m.empty && (!m.ordered || m.start == 1)
+	}
+	return false
+}
+
+// splitRow splits a table row into cells. Escaped pipes are kept in the cells
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	sb := strings.Builder{}
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			sb.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(sb.String()))
+}
+
+// parseAlign returns the alignment of each column in a delimiter row
+func parseAlign(line string) []string {
+	cells := splitRow(line)
+	align := make([]string, len(cells))
+	for i, c := range cells {
+		left, right := strings.HasPrefix(c, ":"), strings.HasSuffix(c, ":")
+		switch {
+		case left && right:
+			align[i] = "center"
+		case left:
+			align[i] = "left"
+		case right:
+			align[i] = "right"
+		}
+	}
+	return align
+}
+
+// parseDocument splits the source into lines and parses all blocks
+func (p *parser) parseDocument(source string) []*block {
+	source = strings.ReplaceAll(strings.ReplaceAll(source, "\r\n", "\n"), "\r", "\n")
+	lines := strings.Split(source, "\n")
+	for i := range lines {
+		lines[i] = expandTabs(lines[i])
+	}
+	return p.parseBlocks(lines)
+}
+
+// parseBlocks parses the blocks in the supplied lines
+func (p *parser) parseBlocks(lines []string) []*block {
+	var blocks []*block
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if isBlank(line) {
+			i++
+			continue
+		}
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil && !(m[2][0] == '~' && strings.Contains(m[3], "~")) {
+			b, next := p.parseFence(lines, i, len(m[1]), m[2], m[3])
+			blocks = append(blocks, b)
+			i = next
+			continue
+		}
+		if m := atxHeadingPattern.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, &block{kind: blockHeading, level: len(m[1]), text: strings.TrimSpace(m[2])})
+			i++
+			continue
+		}
+		if thematicBreakPattern.MatchString(line) {
+			blocks = append(blocks, &block{kind: blockBreak})
+			i++
+			continue
+		}
+		if quotePattern.MatchString(line) {
+			b, next := p.parseQuote(lines, i)
+			blocks = append(blocks, b)
+			i = next
+			continue
+		}
+		if m, ok := parseListMarker(line); ok {
+			b, next := p.parseList(lines, i, m)
+			blocks = append(blocks, b)
+			i = next
+			continue
+		}
+		if indentOf(line) >= 4 {
+			b, next := p.parseIndentedCode(lines, i)
+			blocks = append(blocks, b)
+			i = next
+			continue
+		}
+		if i+1 < len(lines) && strings.Contains(line, "|") && delimiterRowPattern.MatchString(lines[i+1]) {
+			if b, next, ok := p.parseTable(lines, i); ok {
+				blocks = append(blocks, b)
+				i = next
+				continue
+			}
+		}
+		b, next := p.parseParagraph(lines, i)
+		if b != nil {
+			blocks = append(blocks, b)
+		}
+		i = next
+	}
+	return blocks
+}
+
+// parseFence parses a fenced code block
+func (p *parser) parseFence(lines []string, i int, indent int, fence string, info string) (*block, int) {
+	lang, _, _ := strings.Cut(info, " ")
+	b := &block{kind: blockCode, lang: html.UnescapeString(lang)}
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if indentOf(line) < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		code = append(code, stripIndent(line, indent))
+	}
+	if len(code) > 0 {
+		b.text = strings.Join(code, "\n") + "\n"
+	}
+	return b, i
+}
+
+// parseIndentedCode parses a code block where each line is indented with four spaces
+func (p *parser) parseIndentedCode(lines []string, i int) (*block, int) {
+	var code []string
+	for ; i < len(lines); i++ {
+		if !isBlank(lines[i]) && indentOf(lines[i]) < 4 {
+			break
+		}
+		code = append(code, stripIndent(lines[i], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+	return &block{kind: blockCode, text: strings.Join(code, "\n") + "\n"}, i
+}
+
+// parseQuote parses a block quote, including lazy continuation lines of a paragraph
+func (p *parser) parseQuote(lines []string, i int) (*block, int) {
+	var content []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if loc := quotePattern.FindStringIndex(line); loc != nil {
+			content = append(content, line[loc[1]:])
+			continue
+		}
+		if isBlank(line) || len(content) == 0 || isBlank(content[len(content)-1]) || startsBlock(line) {
+			break
+		}
+		content = append(content, line)
+	}
+	return &block{kind: blockQuote, children: p.parseBlocks(content)}, i
+}
+
+// parseList parses all items of a list. The list ends when an item with another type of marker is found
+func (p *parser) parseList(lines []string, i int, first listMarker) (*block, int) {
+	list := &block{kind: blockList, ordered: first.ordered, start: first.start, tight: true}
+	for i < len(lines) {
+		m, ok := parseListMarker(lines[i])
+		if !ok || m.ordered != first.ordered || m.char != first.char || thematicBreakPattern.MatchString(lines[i]) {
+			break
+		}
+
+		content := []string{""}
+		if !m.empty {
+			content[0] = lines[i][m.width:]
+		}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			switch {
+			case isBlank(line):
+				content = append(content, "")
+				continue
+			case indentOf(line) >= m.width:
+				content = append(content, stripIndent(line, m.width))
+				continue
+			case isListItem(line):
+				// a new item, in this list or another one, can't be a continuation line
+			case !isBlank(content[len(content)-1]) && !startsBlock(line):
+				// lazy continuation of a paragraph
+				content = append(content, line)
+				continue
+			}
+			break
+		}
+
+		trailing := 0
+		for len(content) > 1 && isBlank(content[len(content)-1]) {
+			content = content[:len(content)-1]
+			trailing++
+		}
+		item := &block{kind: blockItem, children: p.parseBlocks(content)}
+		list.children = append(list.children, item)
+
+		if len(item.children) > 1 {
+			for _, c := range content[1:] {
+				if isBlank(c) {
+					list.tight = false
+					break
+				}
+			}
+		}
+		if trailing > 0 && i < len(lines) {
+			if next, ok := parseListMarker(lines[i]); ok && next.ordered == first.ordered && next.char == first.char {
+				list.tight = false
+			}
+		}
+	}
+	return list, i
+}
+
+// parseTable parses a GFM table. Returns false if the header and the delimiter row have a different number of columns
+func (p *parser) parseTable(lines []string, i int) (*block, int, bool) {
+	header := splitRow(lines[i])
+	align := parseAlign(lines[i+1])
+	if len(header) != len(align) {
+		return nil, i, false
+	}
+	b := &block{kind: blockTable, header: header, align: align}
+	for i += 2; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) || startsBlock(line) {
+			break
+		}
+		cells := splitRow(line)
+		row := make([]string, len(header))
+		copy(row, cells)
+		b.rows = append(b.rows, row)
+	}
+	return b, i, true
+}
+
+// parseParagraph parses a paragraph, or a setext heading if the paragraph is followed by an underline. Link
+// reference definitions at the beginning of the paragraph are removed from it. Returns nil if the paragraph
+// only contains definitions
+func (p *parser) parseParagraph(lines []string, i int) (*block, int) {
+	var content []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if len(content) > 0 {
+			if m := setextPattern.FindStringSubmatch(line); m != nil {
+				level := 1
+				if m[1][0] == '-' {
+					level = 2
+				}
+				return &block{kind: blockHeading, level: level, text: strings.TrimSpace(strings.Join(content, "\n"))}, i + 1
+			}
+			if isBlank(line) || startsBlock(line) {
+				break
+			}
+		}
+		content = append(content, strings.TrimLeft(line, " "))
+	}
+
+	for len(content) > 0 {
+		m := referencePattern.FindStringSubmatch(content[0])
+		if m == nil {
+			break
+		}
+		label := normalizeLabel(m[1])
+		if _, ok := p.refs[label]; !ok {
+			title := ""
+			if len(m[3]) >= 2 {
+				title = unescape(m[3][1 : len(m[3])-1])
+			}
+			p.refs[label] = reference{href: unescape(strings.TrimSuffix(strings.TrimPrefix(m[2], "<"), ">")), title: title}
+		}
+		content = content[1:]
+	}
+	if len(content) == 0 {
+		return nil, i
+	}
+	return &block{kind: blockParagraph, text: strings.TrimSpace(strings.Join(content, "\n"))}, i
+}
@@ -0,0 +1,489 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type inlineKind int
+
+const (
+	inlineText inlineKind = iota
+	inlineCode
+	inlineEmphasis
+	inlineStrong
+	inlineStrikethrough
+	inlineLink
+	inlineImage
+	inlineSoftBreak
+	inlineHardBreak
+	// inlineDelimiter is a run of *, _ or ~ that hasn't been matched yet
+	inlineDelimiter
+	// inlineBracket is a [ or ![ that hasn't been matched yet
+	inlineBracket
+)
+
+// inline is a single inline element in a paragraph, heading or table cell
+type inline struct {
+	kind     inlineKind
+	text     string
+	href     string
+	title    string
+	children []*inline
+	// delimiter runs
+	delim    byte
+	count    int
+	original int
+	canOpen  bool
+	canClose bool
+	// brackets
+	image  bool
+	active bool
+	// pos is the position in the source after the bracket
+	pos int
+}
+
+var (
+	entityPattern   = regexp.MustCompile(`^&(?:#[xX][0-9a-fA-F]{1,6}|#[0-9]{1,7}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+	autolinkPattern = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\x00-\x20]*)>`)
+	emailPattern    = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+)
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// unescape replaces backslash escapes and entities with the characters they represent
+func unescape(s string) string {
+	if !strings.ContainsAny(s, `\&`) {
+		return s
+	}
+	sb := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			sb.WriteByte(s[i+1])
+			i++
+		case s[i] == '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				sb.WriteString(html.UnescapeString(m))
+				i += len(m) - 1
+				continue
+			}
+			sb.WriteByte('&')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// inlineParser parses the inline content of a single block
+type inlineParser struct {
+	refs  map[string]reference
+	src   string
+	nodes []*inline
+}
+
+// parseInlines parses the inline content of a block
+func (p *parser) parseInlines(src string) []*inline {
+	ip := &inlineParser{refs: p.refs, src: src}
+	return ip.parse()
+}
+
+// addText adds text to the last text node, or creates a new text node
+func (ip *inlineParser) addText(s string) {
+	if n := len(ip.nodes); n > 0 && ip.nodes[n-1].kind == inlineText {
+		ip.nodes[n-1].text += s
+		return
+	}
+	ip.nodes = append(ip.nodes, &inline{kind: inlineText, text: s})
+}
+
+// trimTrailingSpaces removes the spaces at the end of the last text node and returns how many were removed
+func (ip *inlineParser) trimTrailingSpaces() int {
+	n := len(ip.nodes)
+	if n == 0 || ip.nodes[n-1].kind != inlineText {
+		return 0
+	}
+	t := ip.nodes[n-1].text
+	trimmed := strings.TrimRight(t, " ")
+	ip.nodes[n-1].text = trimmed
+	return len(t) - len(trimmed)
+}
+
+func (ip *inlineParser) parse() []*inline {
+	s := ip.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch c {
+		case '\\':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				ip.trimTrailingSpaces()
+				ip.nodes = append(ip.nodes, &inline{kind: inlineHardBreak})
+				i += 2
+				continue
+			}
+			if i+1 < len(s) && isASCIIPunct(s[i+1]) {
+				ip.addText(s[i+1 : i+2])
+				i += 2
+				continue
+			}
+			ip.addText("\\")
+			i++
+		case '\n':
+			if ip.trimTrailingSpaces() >= 2 {
+				ip.nodes = append(ip.nodes, &inline{kind: inlineHardBreak})
+			} else {
+				ip.nodes = append(ip.nodes, &inline{kind: inlineSoftBreak})
+			}
+			i++
+			for i < len(s) && s[i] == ' ' {
+				i++
+			}
+		case '`':
+			i = ip.parseCode(i)
+		case '*', '_', '~':
+			i = ip.parseDelimiter(i)
+		case '[':
+			ip.nodes = append(ip.nodes, &inline{kind: inlineBracket, text: "[", active: true, pos: i + 1})
+			i++
+		case '!':
+			if i+1 < len(s) && s[i+1] == '[' {
+				ip.nodes = append(ip.nodes, &inline{kind: inlineBracket, text: "![", image: true, active: true, pos: i + 2})
+				i += 2
+				continue
+			}
+			ip.addText("!")
+			i++
+		case ']':
+			i = ip.parseCloseBracket(i)
+		case '<':
+			if m := autolinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				ip.nodes = append(ip.nodes, &inline{kind: inlineLink, href: m[1], children: []*inline{{kind: inlineText, text: m[1]}}})
+				i += len(m[0])
+				continue
+			}
+			if m := emailPattern.FindStringSubmatch(s[i:]); m != nil {
+				ip.nodes = append(ip.nodes, &inline{kind: inlineLink, href: "mailto:" + m[1], children: []*inline{{kind: inlineText, text: m[1]}}})
+				i += len(m[0])
+				continue
+			}
+			ip.addText("<")
+			i++
+		case '&':
+			if m := entityPattern.FindString(s[i:]); m != "" {
+				ip.addText(html.UnescapeString(m))
+				i += len(m)
+				continue
+			}
+			ip.addText("&")
+			i++
+		default:
+			j := i + 1
+			for j < len(s) && strings.IndexByte("\\\n`*_~[]!<&", s[j]) < 0 {
+				j++
+			}
+			ip.addText(s[i:j])
+			i = j
+		}
+	}
+	return processEmphasis(ip.nodes)
+}
+
+// parseCode parses a code span. The backticks are emitted as text if there's no closing run of the same length
+func (ip *inlineParser) parseCode(i int) int {
+	s := ip.src
+	start := i
+	for i < len(s) && s[i] == '`' {
+		i++
+	}
+	n := i - start
+	for j := i; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		k := j
+		for k < len(s) && s[k] == '`' {
+			k++
+		}
+		if k-j == n {
+			code := strings.ReplaceAll(s[i:j], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			ip.nodes = append(ip.nodes, &inline{kind: inlineCode, text: code})
+			return k
+		}
+		j = k
+	}
+	ip.addText(s[start:i])
+	return i
+}
+
+// parseDelimiter parses a run of *, _ or ~ and decides if it can open or close emphasis
+func (ip *inlineParser) parseDelimiter(i int) int {
+	s := ip.src
+	c := s[i]
+	start := i
+	for i < len(s) && s[i] == c {
+		i++
+	}
+
+	before, after := ' ', ' '
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(s[:start])
+	}
+	if i < len(s) {
+		after, _ = utf8.DecodeRuneInString(s[i:])
+	}
+	leftFlanking := !unicode.IsSpace(after) && (!isPunct(after) || unicode.IsSpace(before) || isPunct(before))
+	rightFlanking := !unicode.IsSpace(before) && (!isPunct(before) || unicode.IsSpace(after) || isPunct(after))
+
+	d := &inline{kind: inlineDelimiter, delim: c, count: i - start, original: i - start}
+	if c == '_' {
+		d.canOpen = leftFlanking && (!rightFlanking || isPunct(before))
+		d.canClose = rightFlanking && (!leftFlanking || isPunct(after))
+	} else {
+		d.canOpen = leftFlanking
+		d.canClose = rightFlanking
+	}
+	if c == '~' && d.count > 2 {
+		d.canOpen, d.canClose = false, false
+	}
+	ip.nodes = append(ip.nodes, d)
+	return i
+}
+
+// parseCloseBracket tries to create a link or an image from the nodes after the last bracket
+func (ip *inlineParser) parseCloseBracket(i int) int {
+	opener := -1
+	for j := len(ip.nodes) - 1; j >= 0; j-- {
+		if ip.nodes[j].kind == inlineBracket {
+			opener = j
+			break
+		}
+	}
+	if opener == -1 {
+		ip.addText("]")
+		return i + 1
+	}
+	o := ip.nodes[opener]
+	if !o.active {
+		o.kind = inlineText
+		ip.addText("]")
+		return i + 1
+	}
+
+	href, title, next, ok := ip.parseLinkTail(i+1, ip.src[o.pos:i])
+	if !ok {
+		o.kind = inlineText
+		ip.addText("]")
+		return i + 1
+	}
+
+	link := &inline{kind: inlineLink, href: href, title: title, children: processEmphasis(ip.nodes[opener+1:])}
+	if o.image {
+		link.kind = inlineImage
+	} else {
+		// links can't contain other links
+		for _, n := range ip.nodes[:opener] {
+			if n.kind == inlineBracket && !n.image {
+				n.active = false
+			}
+		}
+	}
+	ip.nodes = append(ip.nodes[:opener], link)
+	return next
+}
+
+// parseLinkTail parses the destination and title of an inline link, or the label of a reference link
+func (ip *inlineParser) parseLinkTail(i int, text string) (string, string, int, bool) {
+	s := ip.src
+	if i < len(s) && s[i] == '(' {
+		if href, title, next, ok := parseInlineLink(s, i+1); ok {
+			return href, title, next, true
+		}
+	}
+
+	label := text
+	next := i
+	if i < len(s) && s[i] == '[' {
+		end := strings.IndexByte(s[i:], ']')
+		if end != -1 {
+			if end > 1 {
+				label = s[i+1 : i+end]
+			}
+			next = i + end + 1
+		}
+	}
+	ref, ok := ip.refs[normalizeLabel(label)]
+	if !ok {
+		return "", "", i, false
+	}
+	return ref.href, ref.title, next, true
+}
+
+// parseInlineLink parses (destination "title")
+func parseInlineLink(s string, i int) (string, string, int, bool) {
+	skipSpace := func() {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+			i++
+		}
+	}
+	skipSpace()
+
+	var href string
+	switch {
+	case i < len(s) && s[i] == '<':
+		end := strings.IndexAny(s[i+1:], ">\n")
+		if end == -1 || s[i+1+end] != '>' {
+			return "", "", 0, false
+		}
+		href = s[i+1 : i+1+end]
+		i += end + 2
+	default:
+		start := i
+		depth := 0
+	loop:
+		for ; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '(':
+				depth++
+			case ')':
+				if depth == 0 {
+					break loop
+				}
+				depth--
+			case ' ', '\n':
+				break loop
+			}
+		}
+		href = s[start:min(i, len(s))]
+	}
+
+	hasSpace := i < len(s) && (s[i] == ' ' || s[i] == '\n')
+	skipSpace()
+	var title string
+	if hasSpace && i < len(s) && strings.IndexByte(`"'(`, s[i]) >= 0 {
+		closing := s[i]
+		if closing == '(' {
+			closing = ')'
+		}
+		j := i + 1
+		for ; j < len(s) && s[j] != closing; j++ {
+			if s[j] == '\\' {
+				j++
+			}
+		}
+		if j >= len(s) {
+			return "", "", 0, false
+		}
+		title = unescape(s[i+1 : j])
+		i = j + 1
+		skipSpace()
+	}
+	if i >= len(s) || s[i] != ')' {
+		return "", "", 0, false
+	}
+	return unescape(href), title, i + 1, true
+}
+
+// findOpener finds the delimiter that matches the closer at index i
+func findOpener(nodes []*inline, i int) int {
+	c := nodes[i]
+	for j := i - 1; j >= 0; j-- {
+		o := nodes[j]
+		if o.kind != inlineDelimiter || o.delim != c.delim || !o.canOpen || o.count == 0 {
+			continue
+		}
+		if c.delim == '~' {
+			if o.count == c.count {
+				return j
+			}
+			continue
+		}
+		// the "rule of 3" from the CommonMark specification
+		if (o.canClose || c.canOpen) && (o.original+c.original)%3 == 0 && !(o.original%3 == 0 && c.original%3 == 0) {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// processEmphasis matches all delimiter runs and replaces them with emphasis, strong emphasis and strikethrough
+func processEmphasis(nodes []*inline) []*inline {
+	for i := 0; i < len(nodes); i++ {
+		c := nodes[i]
+		if c.kind != inlineDelimiter || !c.canClose {
+			continue
+		}
+		for c.count > 0 {
+			j := findOpener(nodes, i)
+			if j == -1 {
+				break
+			}
+			o := nodes[j]
+			n := 1
+			kind := inlineEmphasis
+			switch {
+			case c.delim == '~':
+				n = c.count
+				kind = inlineStrikethrough
+			case o.count >= 2 && c.count >= 2:
+				n = 2
+				kind = inlineStrong
+			}
+			o.count -= n
+			c.count -= n
+
+			e := &inline{kind: kind, children: delimitersToText(nodes[j+1 : i])}
+			result := append([]*inline{}, nodes[:j]...)
+			if o.count > 0 {
+				result = append(result, o)
+			}
+			result = append(result, e)
+			k := len(result)
+			nodes = append(result, nodes[i:]...)
+			i = k
+		}
+		if c.count == 0 {
+			nodes = append(nodes[:i], nodes[i+1:]...)
+			i--
+		}
+	}
+	return delimitersToText(nodes)
+}
+
+// delimitersToText converts all delimiters and brackets that are not matched into text
+func delimitersToText(nodes []*inline) []*inline {
+	result := make([]*inline, 0, len(nodes))
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineDelimiter:
+			if n.count == 0 {
+				continue
+			}
+			n = &inline{kind: inlineText, text: strings.Repeat(string(n.delim), n.count)}
+		case inlineBracket:
+			n = &inline{kind: inlineText, text: n.text}
+		}
+		if len(result) > 0 && n.kind == inlineText && result[len(result)-1].kind == inlineText {
+			result[len(result)-1] = &inline{kind: inlineText, text: result[len(result)-1].text + n.text}
+			continue
+		}
+		result = append(result, n)
+	}
+	return result
+}
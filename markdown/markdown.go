@@ -0,0 +1,282 @@
+package markdown
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// DefaultSchemes are the URL schemes allowed in links and images unless something else is configured
+var DefaultSchemes = []string{"http", "https", "mailto"}
+
+// Props configures how Markdown is rendered. All components are optional
+type Props struct {
+	// Heading renders a heading with a level between 1 and 6
+	Heading func(level int, c ...h.Node) h.Node
+	// CodeBlock renders a fenced or indented code block. The language is empty if the block doesn't have an
+	// info string. The code is not escaped
+	CodeBlock func(lang string, code string) h.Node
+	// Link renders a link. The href and title are escaped
+	Link func(href string, title string, c ...h.Node) h.Node
+	// Image renders an image. The src, alt and title are escaped
+	Image func(src string, alt string, title string) h.Node
+	// Schemes are the URL schemes allowed in links and images. Relative URLs are always allowed.
+	// Links with any other scheme, such as javascript:, are rendered as text. Default is DefaultSchemes
+	Schemes []string
+}
+
+// Renderer renders Markdown, CommonMark with GFM tables and strikethrough, into nodes. Since the document is built
+// using the same nodes as the rest of the application it goes through the same escaping, attribute policies and tag
+// hooks. Raw HTML in the document is escaped and rendered as text.
+//
+// Example:
+//
+//	var md = markdown.New(markdown.Props{
+//	  CodeBlock: func(lang string, code string) h.Node {
+//	    return Highlight(lang, code)
+//	  },
+//	})
+//
+//	func Product(p Product) h.Node {
+//	  return h.Article(
+//	    md.Render(p.Description),
+//	  )
+//	}
+type Renderer struct {
+	props Props
+}
+
+// New creates a new renderer. Components that are not set in the props use the default implementation
+func New(props Props) *Renderer {
+	if props.Heading == nil {
+		props.Heading = Heading
+	}
+	if props.CodeBlock == nil {
+		props.CodeBlock = CodeBlock
+	}
+	if props.Link == nil {
+		props.Link = Link
+	}
+	if props.Image == nil {
+		props.Image = Image
+	}
+	if props.Schemes == nil {
+		props.Schemes = DefaultSchemes
+	}
+	return &Renderer{props: props}
+}
+
+var defaultRenderer = New(Props{})
+
+// Render renders the Markdown source using the default components
+func Render(source string) h.Node {
+	return defaultRenderer.Render(source)
+}
+
+// Heading is the default heading component
+func Heading(level int, c ...h.Node) h.Node {
+	switch level {
+	case 1:
+		return h.H1(c...)
+	case 2:
+		return h.H2(c...)
+	case 3:
+		return h.H3(c...)
+	case 4:
+		return h.H4(c...)
+	case 5:
+		return h.H5(c...)
+	}
+	return h.H6(c...)
+}
+
+// CodeBlock is the default code block component. The language is added as a "language-" class on the code tag
+func CodeBlock(lang string, code string) h.Node {
+	return h.Pre(
+		h.Code(
+			h.NodeIf(lang != "", a.Class("language-"+html.EscapeString(lang))),
+			h.Text(html.EscapeString(code)),
+		),
+	)
+}
+
+// Link is the default link component. All links get rel="noopener". The href has already been checked against the
+// allowed schemes of the renderer
+func Link(href string, title string, c ...h.Node) h.Node {
+	return h.A(append([]h.Node{
+		a.Attrib("href", href),
+		h.NodeIf(title != "", a.Attrib("title", title)),
+		a.Rel("noopener"),
+	}, c...)...)
+}
+
+// Image is the default image component
+func Image(src string, alt string, title string) h.Node {
+	return h.Img(
+		a.Attrib("src", src),
+		a.Alt(alt),
+		h.NodeIf(title != "", a.Attrib("title", title)),
+	)
+}
+
+// Render parses the Markdown source and returns the result as a node
+func (r *Renderer) Render(source string) h.Node {
+	p := &parser{refs: make(map[string]reference)}
+	blocks := p.parseDocument(source)
+	return h.Join(r.blocks(p, blocks, false)...)
+}
+
+// allowed returns true if the URL is relative or if it has an allowed scheme
+func (r *Renderer) allowed(url string) bool {
+	i := strings.IndexAny(url, ":/?#")
+	if i <= 0 || url[i] != ':' {
+		return true
+	}
+	scheme := strings.ToLower(url[:i])
+	for _, s := range r.props.Schemes {
+		if scheme == s {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeURL percent-encodes the characters that are not allowed in a URL and escapes the result for an attribute
+func escapeURL(url string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(url); i++ {
+		c := url[i]
+		if c <= ' ' || c >= 0x7f || c == '"' || c == '<' || c == '>' || c == '\\' || c == '`' {
+			sb.WriteString("%" + strings.ToUpper(strconv.FormatInt(int64(c)|0x100, 16)[1:]))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return html.EscapeString(sb.String())
+}
+
+// blocks renders blocks. Paragraphs in a tight list are rendered without a p tag
+func (r *Renderer) blocks(p *parser, blocks []*block, tight bool) []h.Node {
+	result := make([]h.Node, 0, len(blocks))
+	for _, b := range blocks {
+		result = append(result, r.block(p, b, tight))
+	}
+	return result
+}
+
+func (r *Renderer) block(p *parser, b *block, tight bool) h.Node {
+	switch b.kind {
+	case blockParagraph:
+		if tight {
+			return h.Join(r.inlines(p.parseInlines(b.text))...)
+		}
+		return h.P(r.inlines(p.parseInlines(b.text))...)
+	case blockHeading:
+		return r.props.Heading(b.level, r.inlines(p.parseInlines(b.text))...)
+	case blockCode:
+		return r.props.CodeBlock(b.lang, b.text)
+	case blockQuote:
+		return h.Blockquote(r.blocks(p, b.children, false)...)
+	case blockBreak:
+		return h.Hr()
+	case blockList:
+		items := make([]h.Node, 0, len(b.children)+1)
+		for _, item := range b.children {
+			items = append(items, h.Li(r.blocks(p, item.children, b.tight)...))
+		}
+		if !b.ordered {
+			return h.Ul(items...)
+		}
+		if b.start != 1 {
+			items = append([]h.Node{a.Attrib("start", strconv.Itoa(b.start))}, items...)
+		}
+		return h.Ol(items...)
+	case blockTable:
+		return r.table(p, b)
+	}
+	return h.Empty()
+}
+
+// table renders a GFM table
+func (r *Renderer) table(p *parser, b *block) h.Node {
+	cell := func(tag func(c ...h.Node) h.Node, i int, text string) h.Node {
+		c := r.inlines(p.parseInlines(text))
+		if b.align[i] != "" {
+			c = append([]h.Node{a.Style("text-align: " + b.align[i])}, c...)
+		}
+		return tag(c...)
+	}
+
+	header := make([]h.Node, len(b.header))
+	for i, text := range b.header {
+		header[i] = cell(h.Th, i, text)
+	}
+	rows := make([]h.Node, len(b.rows))
+	for n, row := range b.rows {
+		cells := make([]h.Node, len(row))
+		for i, text := range row {
+			cells[i] = cell(h.Td, i, text)
+		}
+		rows[n] = h.Tr(cells...)
+	}
+	return h.Table(
+		h.Thead(h.Tr(header...)),
+		h.NodeIf(len(rows) > 0, h.Tbody(rows...)),
+	)
+}
+
+// plainText returns the text of the inline nodes without any formatting. Used as alt text of images
+func plainText(nodes []*inline) string {
+	sb := strings.Builder{}
+	for _, n := range nodes {
+		switch n.kind {
+		case inlineText, inlineCode:
+			sb.WriteString(n.text)
+		case inlineSoftBreak, inlineHardBreak:
+			sb.WriteByte(' ')
+		default:
+			sb.WriteString(plainText(n.children))
+		}
+	}
+	return sb.String()
+}
+
+func (r *Renderer) inlines(nodes []*inline) []h.Node {
+	result := make([]h.Node, 0, len(nodes))
+	for _, n := range nodes {
+		result = append(result, r.inline(n))
+	}
+	return result
+}
+
+func (r *Renderer) inline(n *inline) h.Node {
+	switch n.kind {
+	case inlineText:
+		return h.Text(html.EscapeString(n.text))
+	case inlineCode:
+		return h.Code(h.Text(html.EscapeString(n.text)))
+	case inlineEmphasis:
+		return h.Em(r.inlines(n.children)...)
+	case inlineStrong:
+		return h.Strong(r.inlines(n.children)...)
+	case inlineStrikethrough:
+		return h.Del(r.inlines(n.children)...)
+	case inlineSoftBreak:
+		return h.Text("\n")
+	case inlineHardBreak:
+		return h.Br()
+	case inlineLink:
+		if !r.allowed(n.href) {
+			return h.Join(r.inlines(n.children)...)
+		}
+		return r.props.Link(escapeURL(n.href), html.EscapeString(n.title), r.inlines(n.children)...)
+	case inlineImage:
+		if !r.allowed(n.href) {
+			return h.Text(html.EscapeString(plainText(n.children)))
+		}
+		return r.props.Image(escapeURL(n.href), html.EscapeString(plainText(n.children)), html.EscapeString(n.title))
+	}
+	return h.Empty()
+}
@@ -0,0 +1,127 @@
+package markdown
+
+import (
+	"github.com/westcoastcode-se/gohtml/dom"
+	"strings"
+	"testing"
+)
+
+func render(source string) string {
+	sb := strings.Builder{}
+	Render(source)(0, &sb)
+	return sb.String()
+}
+
+func TestBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"paragraph", "Hello\nworld", "<p>Hello\nworld</p>"},
+		{"paragraphs", "a\n\nb", "<p>a</p><p>b</p>"},
+		{"atx heading", "# One\n### Three ###", "<h1>One</h1><h3>Three</h3>"},
+		{"setext heading", "One\n===\nTwo\n---", "<h1>One</h1><h2>Two</h2>"},
+		{"thematic break", "a\n\n***\n\nb", "<p>a</p><hr/><p>b</p>"},
+		{"fenced code", "```go\nx := 1 < 2\n```", `<pre><code class="language-go">x := 1 &lt; 2` + "\n</code></pre>"},
+		{"indented code", "    a <b>", "<pre><code>a &lt;b&gt;\n</code></pre>"},
+		{"block quote", "> a\n> b", "<blockquote><p>a\nb</p></blockquote>"},
+		{"bullet list", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"ordered list", "3. a\n4. b", `<ol start="3"><li>a</li><li>b</li></ol>`},
+		{"nested list", "- a\n  - b", "<ul><li>a<ul><li>b</li></ul></li></ul>"},
+		{"loose list", "- a\n\n- b", "<ul><li><p>a</p></li><li><p>b</p></li></ul>"},
+		{"table", "| a | b | c |\n|:--|:-:|--:|\n| 1 | 2 | 3 |",
+			`<table><thead><tr><th style="text-align: left">a</th><th style="text-align: center">b</th>` +
+				`<th style="text-align: right">c</th></tr></thead><tbody><tr><td style="text-align: left">1</td>` +
+				`<td style="text-align: center">2</td><td style="text-align: right">3</td></tr></tbody></table>`},
+		{"table without alignment", "| a |\n|---|\n| \\| |",
+			`<table><thead><tr><th>a</th></tr></thead><tbody><tr><td>|</td></tr></tbody></table>`},
+		{"raw html block", "<div onclick=\"x()\">a</div>", "<p>&lt;div onclick=&#34;x()&#34;&gt;a&lt;/div&gt;</p>"},
+		{"raw script", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := render(test.input); actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestInlines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"emphasis", "*a* _b_", "<p><em>a</em> <em>b</em></p>"},
+		{"strong", "**a** __b__", "<p><strong>a</strong> <strong>b</strong></p>"},
+		{"nested emphasis", "***a***", "<p><em><strong>a</strong></em></p>"},
+		{"emphasis in strong", "**a *b* c**", "<p><strong>a <em>b</em> c</strong></p>"},
+		{"strong in emphasis", "*a **b** c*", "<p><em>a <strong>b</strong> c</em></p>"},
+		{"intraword underscore", "snake_case_name", "<p>snake_case_name</p>"},
+		{"unmatched", "*a", "<p>*a</p>"},
+		{"strikethrough", "~~a~~", "<p><del>a</del></p>"},
+		{"code span", "`a <b> *c*`", "<p><code>a &lt;b&gt; *c*</code></p>"},
+		{"code span with backticks", "`` a`b ``", "<p><code>a`b</code></p>"},
+		{"escaped", `\*a\*`, "<p>*a*</p>"},
+		{"entity", "&copy; &amp; &#65;", "<p>© &amp; A</p>"},
+		{"link", `[a](https://example.com "t")`, `<p><a href="https://example.com" title="t" rel="noopener">a</a></p>`},
+		{"relative link", "[a](/x?a=1&b=2)", `<p><a href="/x?a=1&amp;b=2" rel="noopener">a</a></p>`},
+		{"link with quote", `[a](/x"onclick="y)`, `<p><a href="/x%22onclick=%22y" rel="noopener">a</a></p>`},
+		{"javascript link", "[a](javascript:alert(1))", "<p>a</p>"},
+		{"javascript link upper case", "[a](JAVASCRIPT:alert(1))", "<p>a</p>"},
+		{"data link", "[a](data:text/html,x)", "<p>a</p>"},
+		{"autolink", "<https://example.com>", `<p><a href="https://example.com" rel="noopener">https://example.com</a></p>`},
+		{"javascript autolink", "<javascript:alert(1)>", "<p>javascript:alert(1)</p>"},
+		{"image", `![a *b*](/x.png "t")`, `<p><img src="/x.png" alt="a b" title="t"/></p>`},
+		{"javascript image", "![a](javascript:alert(1))", "<p>a</p>"},
+		{"raw inline html", "a <b onclick=\"x\">b</b>", "<p>a &lt;b onclick=&#34;x&#34;&gt;b&lt;/b&gt;</p>"},
+		{"hard break", "a  \nb", "<p>a<br/>b</p>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := render(test.input); actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+// unsafe returns a description of the first element or attribute in the nodes that can run javascript
+func unsafe(nodes []*dom.Node) string {
+	for _, n := range nodes {
+		if n.Type != dom.ElementNode {
+			continue
+		}
+		if n.Tag == "script" || n.Tag == "style" || n.Tag == "iframe" {
+			return "element " + n.Tag
+		}
+		for _, a := range n.Attrs {
+			value := strings.ToLower(strings.Join(strings.Fields(a.Value), ""))
+			url := a.Key == "href" || a.Key == "src"
+			if strings.HasPrefix(a.Key, "on") || url && strings.Contains(value, "javascript:") {
+				return "attribute " + a.Key + "=" + a.Value
+			}
+		}
+		if s := unsafe(n.Children); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func FuzzRender(f *testing.F) {
+	f.Add("[a](javascript:alert(1))")
+	f.Add("![a](JaVaScRiPt:alert(1))")
+	f.Add("<javascript:alert(1)>")
+	f.Add("| a |\n|:-:|\n| [b](javascript:x) |")
+	f.Add("> - **a *b* `c`**\n>   1. ~~d~~")
+	f.Add("<a href=\"javascript:x\" onclick=\"y\">z</a>")
+	f.Fuzz(func(t *testing.T, input string) {
+		output := render(input)
+		if s := unsafe(dom.Parse(output)); s != "" {
+			t.Errorf("unsafe %s in output %q from %q", s, output, input)
+		}
+	})
+}
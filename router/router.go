@@ -0,0 +1,87 @@
+package router
+
+import (
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/urls"
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// Param are the types that can be used as a path parameter
+type Param interface {
+	~string | ~int | ~int32 | ~int64 | ~uint | ~uint32 | ~uint64
+}
+
+// Mux is where routes are registered, for example a http.ServeMux
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// route contains the pattern and the names of the wildcards shared by all route types
+type route struct {
+	pattern string
+	names   []string
+}
+
+// newRoute verifies that the pattern has the expected number of wildcards. The number of wildcards is the number
+// of type parameters of the route, which is why a call with too few or too many values fails to compile
+func newRoute(pattern string, wildcards int) route {
+	names := urls.Wildcards(pattern)
+	if len(names) != wildcards {
+		panic(fmt.Sprintf("router: pattern %q has %d wildcards but the route expects %d", pattern, len(names),
+			wildcards))
+	}
+	return route{pattern: pattern, names: names}
+}
+
+// url expands the pattern with the values in the same order as the wildcards
+func (r route) url(values ...string) urls.URL {
+	params := make(urls.Params, len(values))
+	for i, v := range values {
+		params[r.names[i]] = v
+	}
+	u, err := urls.Expand(r.pattern, params)
+	if err != nil {
+		// only happens if the same wildcard name is used twice, which http.ServeMux doesn't allow either
+		panic(err)
+	}
+	return u
+}
+
+// format converts a path parameter into a string
+func format[T Param](value T) string {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	default:
+		return strconv.FormatInt(v.Int(), 10)
+	}
+}
+
+// parse reads the path parameter with the supplied name from the request
+func parse[T Param](r *http.Request, name string) (T, bool) {
+	var result T
+	s := r.PathValue(name)
+	v := reflect.ValueOf(&result).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return result, false
+		}
+		v.SetUint(n)
+	default:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return result, false
+		}
+		v.SetInt(n)
+	}
+	return result, true
+}
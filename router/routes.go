@@ -0,0 +1,235 @@
+package router
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"github.com/westcoastcode-se/gohtml/urls"
+	"net/http"
+)
+
+// Route0 is a route without any path parameters.
+//
+// Example:
+//
+//	var Home = router.New("GET /{$}")
+//
+//	func main() {
+//	  mux := http.NewServeMux()
+//	  Home.Handle(mux, home)
+//	}
+//
+//	func Nav() h.Node {
+//	  return h.A(Home.Href(), h.Text("Home"))
+//	}
+type Route0 struct {
+	route
+}
+
+// New creates a route without any path parameters. The pattern uses the same syntax as http.ServeMux.
+// Panics if the pattern contains wildcards
+func New(pattern string) Route0 {
+	return Route0{newRoute(pattern, 0)}
+}
+
+// Pattern returns the pattern used when the route is registered
+func (r Route0) Pattern() string {
+	return r.pattern
+}
+
+// URL returns the URL to the route
+func (r Route0) URL() urls.URL {
+	return r.url()
+}
+
+// Href emits a href attribute with the URL to the route
+func (r Route0) Href() h.Node {
+	return a.Href(r.URL().String())
+}
+
+// Action emits an action attribute with the URL to the route
+func (r Route0) Action() h.Node {
+	return a.Action(r.URL().String())
+}
+
+// Handler returns a http.Handler that calls the function
+func (r Route0) Handler(fn func(w http.ResponseWriter, r *http.Request)) http.Handler {
+	return http.HandlerFunc(fn)
+}
+
+// Handle registers the function as the handler of the route
+func (r Route0) Handle(mux Mux, fn func(w http.ResponseWriter, r *http.Request)) {
+	mux.Handle(r.pattern, r.Handler(fn))
+}
+
+// Route1 is a route with one path parameter.
+//
+// Example:
+//
+//	var Product = router.New1[int]("GET /products/{id}")
+//
+//	func main() {
+//	  mux := http.NewServeMux()
+//	  Product.Handle(mux, func(w http.ResponseWriter, r *http.Request, id int) {
+//	    ...
+//	  })
+//	}
+//
+//	func ProductLink(p Product) h.Node {
+//	  return h.A(Product.Href(p.ID), h.Text(p.Name))
+//	}
+type Route1[A Param] struct {
+	route
+}
+
+// New1 creates a route with one path parameter. The pattern uses the same syntax as http.ServeMux.
+// Panics if the pattern doesn't contain exactly one wildcard
+func New1[A Param](pattern string) Route1[A] {
+	return Route1[A]{newRoute(pattern, 1)}
+}
+
+// Pattern returns the pattern used when the route is registered
+func (r Route1[A]) Pattern() string {
+	return r.pattern
+}
+
+// URL returns the URL to the route
+func (r Route1[A]) URL(v A) urls.URL {
+	return r.url(format(v))
+}
+
+// Href emits a href attribute with the URL to the route
+func (r Route1[A]) Href(v A) h.Node {
+	return a.Href(r.URL(v).String())
+}
+
+// Action emits an action attribute with the URL to the route
+func (r Route1[A]) Action(v A) h.Node {
+	return a.Action(r.URL(v).String())
+}
+
+// Handler returns a http.Handler that parses the path parameter and calls the function. The response is
+// 404 Not Found if the parameter can't be parsed
+func (r Route1[A]) Handler(fn func(w http.ResponseWriter, r *http.Request, a A)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		a, ok := parse[A](req, r.names[0])
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		fn(w, req, a)
+	})
+}
+
+// Handle registers the function as the handler of the route
+func (r Route1[A]) Handle(mux Mux, fn func(w http.ResponseWriter, r *http.Request, a A)) {
+	mux.Handle(r.pattern, r.Handler(fn))
+}
+
+// Route2 is a route with two path parameters.
+//
+// Example:
+//
+//	var File = router.New2[string, string]("GET /users/{user}/files/{path...}")
+//
+//	func FileLink(f File) h.Node {
+//	  return h.A(File.Href(f.Owner, f.Path), h.Text(f.Name))
+//	}
+type Route2[A Param, B Param] struct {
+	route
+}
+
+// New2 creates a route with two path parameters. The pattern uses the same syntax as http.ServeMux.
+// Panics if the pattern doesn't contain exactly two wildcards
+func New2[A Param, B Param](pattern string) Route2[A, B] {
+	return Route2[A, B]{newRoute(pattern, 2)}
+}
+
+// Pattern returns the pattern used when the route is registered
+func (r Route2[A, B]) Pattern() string {
+	return r.pattern
+}
+
+// URL returns the URL to the route
+func (r Route2[A, B]) URL(v1 A, v2 B) urls.URL {
+	return r.url(format(v1), format(v2))
+}
+
+// Href emits a href attribute with the URL to the route
+func (r Route2[A, B]) Href(v1 A, v2 B) h.Node {
+	return a.Href(r.URL(v1, v2).String())
+}
+
+// Action emits an action attribute with the URL to the route
+func (r Route2[A, B]) Action(v1 A, v2 B) h.Node {
+	return a.Action(r.URL(v1, v2).String())
+}
+
+// Handler returns a http.Handler that parses the path parameters and calls the function. The response is
+// 404 Not Found if a parameter can't be parsed
+func (r Route2[A, B]) Handler(fn func(w http.ResponseWriter, r *http.Request, a A, b B)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		a, ok1 := parse[A](req, r.names[0])
+		b, ok2 := parse[B](req, r.names[1])
+		if !ok1 || !ok2 {
+			http.NotFound(w, req)
+			return
+		}
+		fn(w, req, a, b)
+	})
+}
+
+// Handle registers the function as the handler of the route
+func (r Route2[A, B]) Handle(mux Mux, fn func(w http.ResponseWriter, r *http.Request, a A, b B)) {
+	mux.Handle(r.pattern, r.Handler(fn))
+}
+
+// Route3 is a route with three path parameters
+type Route3[A Param, B Param, C Param] struct {
+	route
+}
+
+// New3 creates a route with three path parameters. The pattern uses the same syntax as http.ServeMux.
+// Panics if the pattern doesn't contain exactly three wildcards
+func New3[A Param, B Param, C Param](pattern string) Route3[A, B, C] {
+	return Route3[A, B, C]{newRoute(pattern, 3)}
+}
+
+// Pattern returns the pattern used when the route is registered
+func (r Route3[A, B, C]) Pattern() string {
+	return r.pattern
+}
+
+// URL returns the URL to the route
+func (r Route3[A, B, C]) URL(v1 A, v2 B, v3 C) urls.URL {
+	return r.url(format(v1), format(v2), format(v3))
+}
+
+// Href emits a href attribute with the URL to the route
+func (r Route3[A, B, C]) Href(v1 A, v2 B, v3 C) h.Node {
+	return a.Href(r.URL(v1, v2, v3).String())
+}
+
+// Action emits an action attribute with the URL to the route
+func (r Route3[A, B, C]) Action(v1 A, v2 B, v3 C) h.Node {
+	return a.Action(r.URL(v1, v2, v3).String())
+}
+
+// Handler returns a http.Handler that parses the path parameters and calls the function. The response is
+// 404 Not Found if a parameter can't be parsed
+func (r Route3[A, B, C]) Handler(fn func(w http.ResponseWriter, r *http.Request, a A, b B, c C)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		a, ok1 := parse[A](req, r.names[0])
+		b, ok2 := parse[B](req, r.names[1])
+		c, ok3 := parse[C](req, r.names[2])
+		if !ok1 || !ok2 || !ok3 {
+			http.NotFound(w, req)
+			return
+		}
+		fn(w, req, a, b, c)
+	})
+}
+
+// Handle registers the function as the handler of the route
+func (r Route3[A, B, C]) Handle(mux Mux, fn func(w http.ResponseWriter, r *http.Request, a A, b B, c C)) {
+	mux.Handle(r.pattern, r.Handler(fn))
+}
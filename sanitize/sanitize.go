@@ -0,0 +1,246 @@
+package sanitize
+
+import (
+	"github.com/westcoastcode-se/gohtml/dom"
+	"github.com/westcoastcode-se/gohtml/h"
+	"strings"
+)
+
+// dropContent contains elements where the content is removed together with the element. All other elements that
+// are not allowed are removed, but their content is kept
+var dropContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true, "noscript": true,
+	"textarea": true, "select": true, "title": true, "svg": true, "math": true, "frame": true, "frameset": true,
+	"applet": true, "head": true,
+}
+
+// urlAttributes contains attributes where the value is a URL
+var urlAttributes = map[string]bool{
+	"href": true, "src": true, "cite": true, "action": true, "formaction": true, "poster": true, "background": true,
+	"longdesc": true, "usemap": true,
+}
+
+// Policy decides which elements, attributes, URL schemes and CSS properties that are kept when HTML is sanitized.
+// Everything that isn't explicitly allowed is removed. Event handler attributes, such as onclick, are never allowed.
+//
+// Example:
+//
+//	var comments = sanitize.UGC()
+//
+//	func Comment(c Comment) h.Node {
+//	  return h.Div(a.Class("comment"),
+//	    comments.Node(c.HTML),
+//	  )
+//	}
+type Policy struct {
+	elements map[string]map[string]bool
+	global   map[string]bool
+	schemes  map[string]bool
+	styles   map[string]bool
+	rel      string
+}
+
+// NewPolicy creates a policy that doesn't allow anything. Only the text of the input is kept
+func NewPolicy() *Policy {
+	return &Policy{
+		elements: make(map[string]map[string]bool),
+		global:   make(map[string]bool),
+		schemes:  make(map[string]bool),
+		styles:   make(map[string]bool),
+	}
+}
+
+// AllowElements allows the supplied elements without any attributes
+func (p *Policy) AllowElements(tags ...string) *Policy {
+	for _, t := range tags {
+		t = strings.ToLower(t)
+		if _, ok := p.elements[t]; !ok {
+			p.elements[t] = make(map[string]bool)
+		}
+	}
+	return p
+}
+
+// AllowAttrs allows the supplied attributes on an element. The element is allowed as well
+func (p *Policy) AllowAttrs(tag string, attrs ...string) *Policy {
+	p.AllowElements(tag)
+	for _, a := range attrs {
+		p.elements[strings.ToLower(tag)][strings.ToLower(a)] = true
+	}
+	return p
+}
+
+// AllowGlobalAttrs allows the supplied attributes on all allowed elements
+func (p *Policy) AllowGlobalAttrs(attrs ...string) *Policy {
+	for _, a := range attrs {
+		p.global[strings.ToLower(a)] = true
+	}
+	return p
+}
+
+// AllowSchemes allows URLs with the supplied schemes, such as "https" or "mailto". Relative URLs are always allowed
+func (p *Policy) AllowSchemes(schemes ...string) *Policy {
+	for _, s := range schemes {
+		p.schemes[strings.ToLower(s)] = true
+	}
+	return p
+}
+
+// AllowStyles allows the supplied CSS properties in style attributes. The style attribute must be allowed as well
+func (p *Policy) AllowStyles(properties ...string) *Policy {
+	for _, s := range properties {
+		p.styles[strings.ToLower(s)] = true
+	}
+	return p
+}
+
+// LinkRel sets the rel attribute on all links with a href, replacing any rel attribute in the input.
+// For example "nofollow ugc noopener"
+func (p *Policy) LinkRel(rel string) *Policy {
+	p.rel = rel
+	return p
+}
+
+// BasicFormatting returns a policy that allows paragraphs, lists, quotes, code and inline text formatting
+// without any attributes
+func BasicFormatting() *Policy {
+	return NewPolicy().AllowElements(
+		"p", "br", "b", "strong", "i", "em", "u", "s", "del", "ins", "mark", "small", "sub", "sup",
+		"blockquote", "code", "pre", "ul", "ol", "li",
+	)
+}
+
+// UGC returns a policy suitable for user generated content. It allows everything in BasicFormatting together with
+// headings, tables, images and links with http, https and mailto URLs. Links get rel="nofollow ugc noopener"
+func UGC() *Policy {
+	return BasicFormatting().
+		AllowElements("h1", "h2", "h3", "h4", "h5", "h6", "hr", "dl", "dt", "dd", "table", "thead", "tbody",
+			"tfoot", "tr", "caption").
+		AllowAttrs("a", "href", "title").
+		AllowAttrs("img", "src", "alt", "title", "width", "height").
+		AllowAttrs("abbr", "title").
+		AllowAttrs("q", "cite").
+		AllowAttrs("blockquote", "cite").
+		AllowAttrs("ol", "start").
+		AllowAttrs("th", "colspan", "rowspan").
+		AllowAttrs("td", "colspan", "rowspan").
+		AllowSchemes("http", "https", "mailto").
+		LinkRel("nofollow ugc noopener")
+}
+
+// allowedURL returns true if the URL is relative or if it has an allowed scheme. Whitespace and control characters
+// are ignored by browsers in the scheme, so they are ignored here as well
+func (p *Policy) allowedURL(url string) bool {
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, url)
+	i := strings.IndexAny(cleaned, ":/?#")
+	if i == -1 || cleaned[i] != ':' {
+		return true
+	}
+	return i > 0 && p.schemes[strings.ToLower(cleaned[:i])]
+}
+
+// sanitizeStyle removes all declarations with a property that isn't allowed, or with a value that might load
+// external content or execute code
+func (p *Policy) sanitizeStyle(style string) string {
+	var result []string
+	for _, decl := range strings.Split(style, ";") {
+		property, value, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+		if !p.styles[property] || value == "" {
+			continue
+		}
+		lower := strings.ToLower(value)
+		if strings.ContainsAny(lower, `\<>"'`) || strings.Contains(lower, "url(") ||
+			strings.Contains(lower, "expression") || strings.Contains(lower, "/*") || strings.Contains(lower, "@") {
+			continue
+		}
+		result = append(result, property+": "+value)
+	}
+	return strings.Join(result, "; ")
+}
+
+// attrs returns the allowed attributes of an element
+func (p *Policy) attrs(n *dom.Node, allowed map[string]bool) []dom.Attr {
+	var result []dom.Attr
+	for _, a := range n.Attrs {
+		if strings.HasPrefix(a.Key, "on") || (!allowed[a.Key] && !p.global[a.Key]) {
+			continue
+		}
+		switch {
+		case urlAttributes[a.Key]:
+			if !p.allowedURL(a.Value) {
+				continue
+			}
+		case a.Key == "style":
+			if a.Value = p.sanitizeStyle(a.Value); a.Value == "" {
+				continue
+			}
+		case a.Key == "rel" && p.rel != "":
+			continue
+		}
+		result = append(result, a)
+	}
+	if p.rel != "" && n.Tag == "a" {
+		for _, a := range result {
+			if a.Key == "href" {
+				return append(result, dom.Attr{Key: "rel", Value: p.rel})
+			}
+		}
+	}
+	return result
+}
+
+// sanitize returns the nodes that are left when everything that isn't allowed is removed
+func (p *Policy) sanitize(nodes []*dom.Node) []*dom.Node {
+	var result []*dom.Node
+	for _, n := range nodes {
+		switch n.Type {
+		case dom.TextNode:
+			result = append(result, &dom.Node{Type: dom.TextNode, Data: n.Data})
+		case dom.ElementNode:
+			allowed, ok := p.elements[n.Tag]
+			if !ok || dom.IsRawText(n.Tag) {
+				if !dropContent[n.Tag] && !dom.IsRawText(n.Tag) {
+					result = append(result, p.sanitize(n.Children)...)
+				}
+				continue
+			}
+			result = append(result, &dom.Node{
+				Type:     dom.ElementNode,
+				Tag:      n.Tag,
+				Attrs:    p.attrs(n, allowed),
+				Children: p.sanitize(n.Children),
+			})
+		}
+	}
+	return result
+}
+
+// Sanitize parses the HTML and returns the nodes that are allowed by the policy
+func (p *Policy) Sanitize(html string) []*dom.Node {
+	return p.sanitize(dom.Parse(html))
+}
+
+// String returns the sanitized HTML
+func (p *Policy) String(html string) string {
+	sb := strings.Builder{}
+	for _, n := range p.Sanitize(html) {
+		sb.WriteString(n.HTML())
+	}
+	return sb.String()
+}
+
+// Node parses and sanitizes the HTML when the node is created. The result is emitted through the normal writer, which
+// means that all text and attribute values are escaped again
+func (p *Policy) Node(html string) h.Node {
+	return dom.Nodes(p.Sanitize(html))
+}
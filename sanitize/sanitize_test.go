@@ -0,0 +1,168 @@
+package sanitize
+
+import (
+	"github.com/westcoastcode-se/gohtml/dom"
+	"strings"
+	"testing"
+)
+
+func TestUGC(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"text", "Hello & world", "Hello &amp; world"},
+		{"formatting", "<p><b>bold</b> <em>em</em></p>", "<p><b>bold</b> <em>em</em></p>"},
+		{"unknown element keeps content", "<span>x</span>", "x"},
+		{"link", `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow ugc noopener">x</a>`},
+		{"relative link", `<a href="/x">x</a>`, `<a href="/x" rel="nofollow ugc noopener">x</a>`},
+		{"rel is replaced", `<a href="/x" rel="author">x</a>`, `<a href="/x" rel="nofollow ugc noopener">x</a>`},
+		{"attribute not allowed", `<p class="x" id="y">x</p>`, `<p>x</p>`},
+		{"image", `<img src="/x.png" alt="x">`, `<img src="/x.png" alt="x"/>`},
+	}
+	p := UGC()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := p.String(test.input)
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestXSS(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"javascript", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"javascript upper case", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a>x</a>`},
+		{"javascript with leading space", `<a href=" javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"javascript with tab", "<a href=\"java\tscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"javascript with newline", "<a href=\"java\nscript:alert(1)\">x</a>", `<a>x</a>`},
+		{"javascript with nul", "<a href=\"java\x00script:alert(1)\">x</a>", `<a>x</a>`},
+		{"javascript with entity", `<a href="java&#x09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"javascript with encoded colon", `<a href="javascript&colon;alert(1)">x</a>`, `<a>x</a>`},
+		{"vbscript", `<a href="vbscript:msgbox(1)">x</a>`, `<a>x</a>`},
+		{"data image", `<img src="data:text/html;base64,PHNjcmlwdD4=">`, `<img/>`},
+		{"onclick", `<p onclick="alert(1)">x</p>`, `<p>x</p>`},
+		{"onerror", `<img src="/x.png" onerror="alert(1)">`, `<img src="/x.png"/>`},
+		{"upper case event handler", `<p ONMOUSEOVER="alert(1)">x</p>`, `<p>x</p>`},
+		{"script", `<script>alert(1)</script>x`, `x`},
+		{"script in text", `<p><script>alert(1)</script></p>`, `<p></p>`},
+		{"style element", `<style>body{}</style>x`, `x`},
+		{"svg", `<svg><script>alert(1)</script><a href="javascript:alert(1)">x</a></svg>y`, `y`},
+		{"svg onload", `<svg onload="alert(1)">x</svg>`, ``},
+		{"math", `<math><mi xlink:href="javascript:alert(1)">x</mi></math>y`, `y`},
+		{"iframe", `<iframe src="https://example.com">x</iframe>`, ``},
+		{"escaped text", `&lt;script&gt;alert(1)&lt;/script&gt;`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"attribute breakout", `<a title="&quot;><script>alert(1)</script>">x</a>`,
+			`<a title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;">x</a>`},
+	}
+	p := UGC()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := p.String(test.input)
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"allowed", `<p style="color: red">x</p>`, `<p style="color: red">x</p>`},
+		{"not allowed", `<p style="position: fixed; color: red">x</p>`, `<p style="color: red">x</p>`},
+		{"url", `<p style="background: url(https://example.com/x.png)">x</p>`, `<p>x</p>`},
+		{"url upper case", `<p style="background: URL(javascript:alert(1))">x</p>`, `<p>x</p>`},
+		{"expression", `<p style="color: expression(alert(1))">x</p>`, `<p>x</p>`},
+		{"escaped url", `<p style="background: u\72l(x)">x</p>`, `<p>x</p>`},
+		{"comment", `<p style="background: u/**/rl(x)">x</p>`, `<p>x</p>`},
+		{"import", `<p style="color: red; @import 'x'">x</p>`, `<p style="color: red">x</p>`},
+	}
+	p := NewPolicy().AllowAttrs("p", "style").AllowStyles("color", "background")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := p.String(test.input)
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"unterminated script attribute", `<script a="`, ``},
+		{"unterminated textarea attribute", `<textarea x='`, ``},
+		{"unterminated style attribute", `<style a="x`, ``},
+		{"unterminated href", `<a href="javascript:alert(1)`, `<a></a>`},
+		{"unterminated tag", `<b`, `<b></b>`},
+		{"unclosed elements", `<p><b>x`, `<p><b>x</b></p>`},
+		{"unterminated comment", `x<!-- <script>alert(1)</script>`, `x`},
+		{"nested quotes", `<a href="/x"title="y">x</a>`, `<a href="/x" title="y" rel="nofollow ugc noopener">x</a>`},
+	}
+	p := UGC()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := p.String(test.input)
+			if actual != test.expected {
+				t.Errorf("expected %q but was %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNode(t *testing.T) {
+	sb := strings.Builder{}
+	UGC().Node(`<a href="javascript:alert(1)" onclick="x()">x</a>`)(0, &sb)
+	if expected := `<a>x</a>`; sb.String() != expected {
+		t.Errorf("expected %q but was %q", expected, sb.String())
+	}
+}
+
+// unsafe returns a description of the first node that the policy should have removed
+func unsafe(p *Policy, nodes []*dom.Node) string {
+	for _, n := range nodes {
+		if n.Type != dom.ElementNode {
+			continue
+		}
+		if _, ok := p.elements[n.Tag]; !ok {
+			return "element " + n.Tag
+		}
+		for _, a := range n.Attrs {
+			if strings.HasPrefix(a.Key, "on") || urlAttributes[a.Key] && !p.allowedURL(a.Value) {
+				return "attribute " + a.Key + "=" + a.Value
+			}
+		}
+		if s := unsafe(p, n.Children); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func FuzzSanitize(f *testing.F) {
+	f.Add(`<a href="javascript:alert(1)">x</a>`)
+	f.Add(`<script a="`)
+	f.Add(`<p style="color: red" onclick="x">y</p>`)
+	p := UGC()
+	f.Fuzz(func(t *testing.T, input string) {
+		// the sanitized output must still be safe when it's parsed again
+		if s := unsafe(p, dom.Parse(p.String(input))); s != "" {
+			t.Errorf("unsafe %s in output from %q", s, input)
+		}
+	})
+}
@@ -0,0 +1,346 @@
+package sri
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"hash"
+	"hash/maphash"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Algorithm is a hash algorithm supported by Subresource Integrity
+type Algorithm string
+
+const (
+	SHA256 Algorithm = "sha256"
+	SHA384 Algorithm = "sha384"
+	SHA512 Algorithm = "sha512"
+)
+
+// ErrUnsupportedAlgorithm is returned if an algorithm isn't one of SHA256, SHA384 or SHA512
+var ErrUnsupportedAlgorithm = errors.New("sri: unsupported algorithm")
+
+// ErrNotDeclared is returned when Declared is used for an asset that isn't in Props.Declarations
+var ErrNotDeclared = errors.New("sri: integrity not declared")
+
+// MismatchError is returned when a declared integrity doesn't match the content of an asset
+type MismatchError struct {
+	Name     string
+	Declared string
+	Actual   string
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("sri: integrity of %s is %s but %s was declared", e.Name, e.Actual, e.Declared)
+}
+
+// newHash creates a new hash for the algorithm
+func (alg Algorithm) newHash() (hash.Hash, error) {
+	switch alg {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA384:
+		return sha512.New384(), nil
+	case SHA512:
+		return sha512.New(), nil
+	}
+	return nil, ErrUnsupportedAlgorithm
+}
+
+// strength is used to find the strongest algorithm in a list of integrity values
+func (alg Algorithm) strength() int {
+	switch alg {
+	case SHA256:
+		return 1
+	case SHA384:
+		return 2
+	case SHA512:
+		return 3
+	}
+	return 0
+}
+
+// Compute returns the integrity value, for example "sha384-oqVuAfXRKap7fdgcCY5uykM6+R9GqQ8K/uxy9rx7HNQlGYl1kPzQho1wx4JwY8wC",
+// of the supplied content
+func Compute(alg Algorithm, content []byte) (string, error) {
+	hh, err := alg.newHash()
+	if err != nil {
+		return "", err
+	}
+	_, _ = hh.Write(content)
+	return string(alg) + "-" + base64.StdEncoding.EncodeToString(hh.Sum(nil)), nil
+}
+
+// ComputeFile returns the integrity value of a file on the local file system
+func ComputeFile(alg Algorithm, path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return Compute(alg, content)
+}
+
+// Matches returns true if the content matches the supplied integrity metadata. The metadata might contain more than
+// one value separated by whitespace. Just like a browser, only values using the strongest algorithm are compared
+func Matches(integrity string, content []byte) bool {
+	var values []string
+	strongest := 0
+	for _, v := range strings.Fields(integrity) {
+		// Options, such as "sha256-abc?foo", are not used by any browser yet
+		v, _, _ = strings.Cut(v, "?")
+		alg, _, ok := strings.Cut(v, "-")
+		if !ok {
+			continue
+		}
+		s := Algorithm(alg).strength()
+		switch {
+		case s == 0 || s < strongest:
+			continue
+		case s > strongest:
+			strongest = s
+			values = values[:0]
+		}
+		values = append(values, v)
+	}
+	for _, v := range values {
+		alg, _, _ := strings.Cut(v, "-")
+		actual, err := Compute(Algorithm(alg), content)
+		if err == nil && actual == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Props configures how assets are linked
+type Props struct {
+	// Algorithm used when computing the integrity of an asset. Default is SHA384
+	Algorithm Algorithm
+	// Prefix is put in front of the name of an asset to create the URL of the asset. Default is "/"
+	Prefix string
+	// CrossOrigin is the value of the crossorigin attribute. Default is a.CrossOriginAnonymous
+	CrossOrigin string
+	// Declarations are integrity values known ahead of time, indexed by the name of the asset. They are emitted
+	// by Declared and checked by Verify, even if they are never rendered
+	Declarations map[string]string
+}
+
+// entry is a cached integrity value. The value is computed again if the content of the file changes, which is
+// detected using a fingerprint of the content that is a lot cheaper to compute than the digest itself
+type entry struct {
+	fingerprint uint64
+	integrity   string
+}
+
+// seed is used when computing the fingerprint of the content of a file
+var seed = maphash.MakeSeed()
+
+// Assets computes and caches the integrity of assets in a file system, such as an embed.FS
+//
+// Example:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	var assets = sri.New(must(fs.Sub(static, "static")), sri.Props{Prefix: "/static/"})
+//
+//	func Index() h.Node {
+//	  return h.Head(
+//	    assets.Stylesheet("app.css"),
+//	    assets.Script("app.js"),
+//	  )
+//	}
+type Assets struct {
+	fsys         fs.FS
+	props        Props
+	mutex        sync.Mutex
+	cache        map[string]entry
+	declarations map[string]map[string]struct{}
+}
+
+// New creates a new Assets instance for the supplied file system
+func New(fsys fs.FS, props Props) *Assets {
+	if props.Algorithm == "" {
+		props.Algorithm = SHA384
+	}
+	if props.Prefix == "" {
+		props.Prefix = "/"
+	}
+	if props.CrossOrigin == "" {
+		props.CrossOrigin = a.CrossOriginAnonymous
+	}
+	s := &Assets{
+		fsys:         fsys,
+		props:        props,
+		cache:        make(map[string]entry),
+		declarations: make(map[string]map[string]struct{}),
+	}
+	for name, integrity := range props.Declarations {
+		s.declare(name, integrity)
+	}
+	return s
+}
+
+// Dir creates a new Assets instance for files in a directory on the local file system
+func Dir(dir string, props Props) *Assets {
+	return New(os.DirFS(dir), props)
+}
+
+// URL returns the URL of the asset with the supplied name
+func (s *Assets) URL(name string) string {
+	return s.props.Prefix + name
+}
+
+// Digest returns the integrity value of the asset with the supplied name. The digest is cached by the content
+// of the file
+func (s *Assets) Digest(name string) (string, error) {
+	content, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return "", err
+	}
+	fingerprint := maphash.Bytes(seed, content)
+
+	s.mutex.Lock()
+	e, ok := s.cache[name]
+	s.mutex.Unlock()
+	if ok && e.fingerprint == fingerprint {
+		return e.integrity, nil
+	}
+
+	integrity, err := Compute(s.props.Algorithm, content)
+	if err != nil {
+		return "", err
+	}
+	s.mutex.Lock()
+	s.cache[name] = entry{
+		fingerprint: fingerprint,
+		integrity:   integrity,
+	}
+	s.mutex.Unlock()
+	return integrity, nil
+}
+
+// Integrity emits the integrity and crossorigin attributes for the asset with the supplied name. The rendering
+// fails if the asset can't be read
+func (s *Assets) Integrity(name string) h.Node {
+	return func(b byte, w io.Writer) byte {
+		integrity, err := s.Digest(name)
+		if err != nil {
+			return h.Fail(err)(b, w)
+		}
+		b = a.Integrity(integrity)(b, w)
+		return a.CrossOrigin(s.props.CrossOrigin)(b, w)
+	}
+}
+
+// declare remembers an integrity value so that it can be checked by Verify. The same value is only stored once
+func (s *Assets) declare(name string, integrity string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	values, ok := s.declarations[name]
+	if !ok {
+		values = make(map[string]struct{}, 1)
+		s.declarations[name] = values
+	}
+	values[integrity] = struct{}{}
+}
+
+// Declare emits the integrity and crossorigin attributes using an integrity value that is known ahead of time,
+// for example one that is copied from a release note of a library. The declaration is checked against the
+// content of the asset by Verify, but only after the node has been created. Use Props.Declarations and Declared
+// if Verify must see the value before anything is rendered
+func (s *Assets) Declare(name string, integrity string) h.Node {
+	s.declare(name, integrity)
+	return h.Join(
+		a.Integrity(integrity),
+		a.CrossOrigin(s.props.CrossOrigin),
+	)
+}
+
+// Declared emits the integrity and crossorigin attributes using the value in Props.Declarations. The rendering
+// fails if the asset isn't declared
+func (s *Assets) Declared(name string) h.Node {
+	integrity, ok := s.props.Declarations[name]
+	if !ok {
+		return h.Fail(fmt.Errorf("%w: %s", ErrNotDeclared, name))
+	}
+	return h.Join(
+		a.Integrity(integrity),
+		a.CrossOrigin(s.props.CrossOrigin),
+	)
+}
+
+// Verify checks that all values in Props.Declarations, and all values declared with Declare, match the content of
+// the assets. It's intended to be called
+// as part of the build, for example from a test or a go:generate program, so that a mismatch is found before it
+// reaches a browser that refuses to load the asset
+//
+// Example:
+//
+//	func TestAssets(t *testing.T) {
+//	  if err := assets.Verify(); err != nil {
+//	    t.Fatal(err)
+//	  }
+//	}
+func (s *Assets) Verify() error {
+	s.mutex.Lock()
+	declarations := make(map[string][]string, len(s.declarations))
+	for name, values := range s.declarations {
+		for integrity := range values {
+			declarations[name] = append(declarations[name], integrity)
+		}
+	}
+	s.mutex.Unlock()
+
+	names := make([]string, 0, len(declarations))
+	for name := range declarations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		content, err := fs.ReadFile(s.fsys, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values := declarations[name]
+		sort.Strings(values)
+		for _, integrity := range values {
+			if !Matches(integrity, content) {
+				actual, _ := s.Digest(name)
+				errs = append(errs, &MismatchError{Name: name, Declared: integrity, Actual: actual})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MustVerify calls Verify and panics if any declaration is wrong. Useful when the application is starting
+func (s *Assets) MustVerify() {
+	if err := s.Verify(); err != nil {
+		panic(err)
+	}
+}
+
+// Script emits a script tag that loads the asset with the supplied name. Use h.UseScript together with
+// Integrity if you want the script to be put in the head of the document
+func (s *Assets) Script(name string, c ...h.Node) h.Node {
+	return h.Script(append([]h.Node{a.Src(s.URL(name)), s.Integrity(name)}, c...)...)
+}
+
+// Stylesheet emits a link tag that loads the stylesheet with the supplied name
+func (s *Assets) Stylesheet(name string, c ...h.Node) h.Node {
+	return h.Link(append([]h.Node{a.Rel(a.RelStylesheet), a.Href(s.URL(name)), s.Integrity(name)}, c...)...)
+}
@@ -0,0 +1,57 @@
+package sri
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVerify(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("alert(1)")}}
+	integrity, err := Compute(SHA384, []byte("alert(1)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := New(fsys, Props{Declarations: map[string]string{"app.js": integrity}})
+	if err := s.Verify(); err != nil {
+		t.Errorf("expected declarations to match but was %v", err)
+	}
+
+	s = New(fsys, Props{Declarations: map[string]string{"app.js": "sha384-invalid"}})
+	var mismatch *MismatchError
+	if err := s.Verify(); !errors.As(err, &mismatch) || mismatch.Actual != integrity {
+		t.Errorf("expected a mismatch before anything is rendered but was %v", err)
+	}
+}
+
+func TestDeclareIsStoredOnce(t *testing.T) {
+	s := New(fstest.MapFS{"app.js": {Data: []byte("x")}}, Props{})
+	for i := 0; i < 10; i++ {
+		s.Declare("app.js", "sha384-a")
+	}
+	s.Declare("app.js", "sha384-b")
+	if n := len(s.declarations["app.js"]); n != 2 {
+		t.Errorf("expected 2 declarations but was %d", n)
+	}
+	if err := s.Verify(); err == nil {
+		t.Error("expected both declarations to be wrong")
+	}
+}
+
+func TestDigestIsCachedByContent(t *testing.T) {
+	// the size and the modification time are the same after the change
+	fsys := fstest.MapFS{"app.js": {Data: []byte("a()")}}
+	s := New(fsys, Props{})
+	for _, content := range []string{"a()", "b()", "a()"} {
+		fsys["app.js"].Data = []byte(content)
+		expected, _ := Compute(SHA384, []byte(content))
+		actual, err := s.Digest("app.js")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("expected %q for %q but was %q", expected, content, actual)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package sse
+
+import (
+	"bytes"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultKeepAlive is the keep-alive interval used if none is configured
+const DefaultKeepAlive = 15 * time.Second
+
+// Event is a single server-sent event where the data is a rendered node
+type Event struct {
+	// Name of the event. The client receives it as a "message" event if empty
+	Name string
+	// ID of the event. The client sends the last received id in the Last-Event-ID header when it reconnects
+	ID string
+	// Retry tells the client how long to wait before reconnecting. Ignored if zero
+	Retry time.Duration
+	// Node is the content of the event
+	Node h.Node
+}
+
+// Props configures how events are streamed
+type Props struct {
+	// KeepAlive is the interval in which a comment is sent to keep the connection open. DefaultKeepAlive is used
+	// if zero. Keep-alives are disabled if negative
+	KeepAlive time.Duration
+}
+
+// clean removes line breaks, since they are not allowed in the event name or id
+func clean(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// writeEvent renders the node in the event and writes it using the event stream format. Each line in the rendered
+// content is written as its own data field
+func writeEvent(buf *bytes.Buffer, r *http.Request, e Event) error {
+	content := bytes.Buffer{}
+	if e.Node != nil {
+		if _, err := h.Fragment(e.Node)(h.WithContext(r.Context(), &content)); err != nil {
+			return err
+		}
+	}
+	if e.ID != "" {
+		buf.WriteString("id: ")
+		buf.WriteString(clean(e.ID))
+		buf.WriteByte('\n')
+	}
+	if e.Name != "" {
+		buf.WriteString("event: ")
+		buf.WriteString(clean(e.Name))
+		buf.WriteByte('\n')
+	}
+	if e.Retry > 0 {
+		buf.WriteString("retry: ")
+		buf.WriteString(strconv.FormatInt(e.Retry.Milliseconds(), 10))
+		buf.WriteByte('\n')
+	}
+	data := strings.ReplaceAll(content.String(), "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		buf.WriteString("data: ")
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return nil
+}
+
+// Stream writes all events from the supplied channel to the client until the channel is closed or the client
+// disconnects. The producer is expected to stop sending events when the context of the request is done.
+// Returns the context error if the client disconnected
+//
+// Example:
+//
+//	func clock(w http.ResponseWriter, r *http.Request) {
+//	  ch := make(chan sse.Event)
+//	  go func() {
+//	    defer close(ch)
+//	    for {
+//	      select {
+//	      case <-r.Context().Done():
+//	        return
+//	      case t := <-time.After(time.Second):
+//	        ch <- sse.Event{Name: "clock", Node: h.Span(h.Text(t.Format(time.TimeOnly)))}
+//	      }
+//	    }
+//	  }()
+//	  _ = sse.Stream(w, r, ch, sse.Props{})
+//	}
+func Stream(w http.ResponseWriter, r *http.Request, events <-chan Event, props Props) error {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return err
+	}
+
+	keepAlive := props.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+	var tick <-chan time.Time
+	if keepAlive > 0 {
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	buf := bytes.Buffer{}
+	for {
+		buf.Reset()
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case <-tick:
+			buf.WriteString(": keep-alive\n\n")
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(&buf, r, e); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// StreamNodes writes all nodes from the supplied channel as events with the supplied name. This works with the
+// same type of channel as h.EmitChannel. The stream stops when the client disconnects, even if the channel is
+// never closed
+func StreamNodes(w http.ResponseWriter, r *http.Request, name string, nodes <-chan h.Node, props Props) error {
+	events := make(chan Event)
+	done := make(chan struct{})
+	defer close(done)
+	ctx := r.Context()
+	go func() {
+		defer close(events)
+		for {
+			// the goroutine must never block on the channels alone, since it would leak if the client disconnects
+			// while the producer neither sends nor closes the channel
+			select {
+			case n, ok := <-nodes:
+				if !ok {
+					return
+				}
+				select {
+				case events <- Event{Name: name, Node: n}:
+				case <-ctx.Done():
+					return
+				case <-done:
+					return
+				}
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	}()
+	return Stream(w, r, events, props)
+}
+
+// Handler creates a http.Handler that streams the events from the channel returned by the supplied function. The
+// function is called once for each request
+func Handler(f func(r *http.Request) chan Event, props Props) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = Stream(w, r, f(r), props)
+	})
+}
+
+// LastEventID returns the id of the last event the client received before it reconnected
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
@@ -0,0 +1,36 @@
+package sse
+
+import (
+	"context"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStreamNodesStopsWhenClientDisconnects(t *testing.T) {
+	before := runtime.NumGoroutine()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	// the producer neither sends nor closes the channel
+	nodes := make(chan h.Node)
+	result := make(chan error)
+	go func() {
+		result <- StreamNodes(w, r, "x", nodes, Props{KeepAlive: -1})
+	}()
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Errorf("expected %v but was %v", context.Canceled, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected %d goroutines but was %d", before, n)
+	}
+}
@@ -0,0 +1,173 @@
+package urls
+
+import (
+	"errors"
+	"fmt"
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownRoute is returned when a URL is requested for a route that doesn't exist
+	ErrUnknownRoute = errors.New("urls: unknown route")
+	// ErrMissingParam is returned when a parameter in the pattern of a route isn't supplied
+	ErrMissingParam = errors.New("urls: missing parameter")
+	// ErrUnknownParam is returned when a parameter is supplied that isn't in the pattern of a route
+	ErrUnknownParam = errors.New("urls: unknown parameter")
+)
+
+// Params are the values of the wildcards in a route pattern
+type Params map[string]string
+
+// Wildcards returns the names of the wildcards in a pattern, such as "GET /users/{id}/files/{path...}"
+func Wildcards(pattern string) []string {
+	var result []string
+	for _, s := range strings.Split(pathOf(pattern), "/") {
+		if strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") && s != "{$}" {
+			result = append(result, strings.TrimSuffix(s[1:len(s)-1], "..."))
+		}
+	}
+	return result
+}
+
+// pathOf removes the method and host from a pattern
+func pathOf(pattern string) string {
+	if _, p, ok := strings.Cut(pattern, " "); ok {
+		pattern = strings.TrimLeft(p, " \t")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
+// Expand creates a URL by replacing the wildcards in a pattern, using the same syntax as http.ServeMux, with the
+// escaped values of the params. The method and the host in the pattern are ignored. A {name...} wildcard matches the
+// rest of the path, which means that slashes in the value are kept
+//
+// Example:
+//
+//	urls.Expand("GET /users/{id}/files/{path...}", urls.Params{"id": "42", "path": "docs/a b.txt"})
+//	// "/users/42/files/docs/a%20b.txt"
+func Expand(pattern string, params Params) (URL, error) {
+	used := 0
+	segments := strings.Split(pathOf(pattern), "/")
+	for i, s := range segments {
+		if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+			continue
+		}
+		if s == "{$}" {
+			segments[i] = ""
+			continue
+		}
+		name := s[1 : len(s)-1]
+		rest := strings.HasSuffix(name, "...")
+		name = strings.TrimSuffix(name, "...")
+		value, ok := params[name]
+		if !ok {
+			return URL{}, fmt.Errorf("%w %s in %s", ErrMissingParam, name, pattern)
+		}
+		used++
+		if rest {
+			parts := strings.Split(value, "/")
+			for j := range parts {
+				parts[j] = url.PathEscape(parts[j])
+			}
+			segments[i] = strings.Join(parts, "/")
+		} else {
+			segments[i] = url.PathEscape(value)
+		}
+	}
+	if used != len(params) {
+		for name := range params {
+			found := false
+			for _, w := range Wildcards(pattern) {
+				found = found || w == name
+			}
+			if !found {
+				return URL{}, fmt.Errorf("%w %s in %s", ErrUnknownParam, name, pattern)
+			}
+		}
+	}
+	return Raw(strings.Join(segments, "/")), nil
+}
+
+// Routes contains named routes, which makes it possible to create links to a route without hard-coding the path.
+//
+// Example:
+//
+//	var routes = urls.NewRoutes()
+//
+//	func main() {
+//	  http.HandleFunc(routes.Add("product", "GET /products/{id}"), product)
+//	}
+//
+//	func ProductLink(p Product) h.Node {
+//	  return h.A(routes.Href("product", urls.Params{"id": p.ID}), h.Text(p.Name))
+//	}
+type Routes struct {
+	mutex    sync.RWMutex
+	patterns map[string]string
+}
+
+// NewRoutes creates a new, empty, set of named routes
+func NewRoutes() *Routes {
+	return &Routes{patterns: make(map[string]string)}
+}
+
+// Add adds a named route and returns the pattern, so that it can be registered in a http.ServeMux at the same time
+func (r *Routes) Add(name string, pattern string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.patterns[name] = pattern
+	return pattern
+}
+
+// Pattern returns the pattern of a named route
+func (r *Routes) Pattern(name string) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	p, ok := r.patterns[name]
+	return p, ok
+}
+
+// URL creates a URL to a named route
+func (r *Routes) URL(name string, params Params) (URL, error) {
+	pattern, ok := r.Pattern(name)
+	if !ok {
+		return URL{}, fmt.Errorf("%w %s", ErrUnknownRoute, name)
+	}
+	return Expand(pattern, params)
+}
+
+// MustURL creates a URL to a named route and panics if the route doesn't exist or if the parameters are wrong
+func (r *Routes) MustURL(name string, params Params) URL {
+	u, err := r.URL(name, params)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// Href emits a href attribute with the URL to a named route. The rendering fails if the route doesn't exist or
+// if the parameters are wrong
+func (r *Routes) Href(name string, params Params) h.Node {
+	u, err := r.URL(name, params)
+	if err != nil {
+		return h.Fail(err)
+	}
+	return a.Href(u.String())
+}
+
+// Action emits an action attribute with the URL to a named route. The rendering fails if the route doesn't exist or
+// if the parameters are wrong
+func (r *Routes) Action(name string, params Params) h.Node {
+	u, err := r.URL(name, params)
+	if err != nil {
+		return h.Fail(err)
+	}
+	return a.Action(u.String())
+}
@@ -0,0 +1,108 @@
+package urls
+
+import (
+	"github.com/westcoastcode-se/gohtml/a"
+	"github.com/westcoastcode-se/gohtml/h"
+	"net/url"
+	"strings"
+)
+
+// URL is a relative or absolute URL that is built from a path, query parameters and a fragment. All parts are
+// encoded when the URL is converted into a string. A URL is never modified, instead each method returns a copy.
+//
+// Example:
+//
+//	u := urls.Path("products", category).Query("sort", "price").Query("q", search)
+//	h.A(u.Href(), h.Text("Products"))
+type URL struct {
+	base     string
+	path     string
+	query    url.Values
+	fragment string
+}
+
+// Path creates a URL from the supplied path segments. Each segment is escaped, which means that a segment can't
+// add more segments to the path even if it contains a slash
+//
+// Example:
+//
+//	urls.Path("users", "a/b") // "/users/a%2Fb"
+func Path(segments ...string) URL {
+	sb := strings.Builder{}
+	for _, s := range segments {
+		sb.WriteByte('/')
+		sb.WriteString(url.PathEscape(s))
+	}
+	if sb.Len() == 0 {
+		sb.WriteByte('/')
+	}
+	return URL{path: sb.String()}
+}
+
+// Raw creates a URL from a path that is already escaped, for example "/static/app.css"
+func Raw(path string) URL {
+	return URL{path: path}
+}
+
+// Absolute creates a URL with the supplied scheme and host, such as "https://example.com", in front of the path
+func Absolute(base string, segments ...string) URL {
+	u := Path(segments...)
+	u.base = strings.TrimSuffix(base, "/")
+	return u
+}
+
+// Query returns a copy of the URL with the supplied values added to the query parameter
+func (u URL) Query(key string, values ...string) URL {
+	q := make(url.Values, len(u.query)+1)
+	for k, v := range u.query {
+		q[k] = v
+	}
+	q[key] = append(q[key][:len(q[key]):len(q[key])], values...)
+	u.query = q
+	return u
+}
+
+// Params returns a copy of the URL with all supplied query parameters added
+func (u URL) Params(values url.Values) URL {
+	for k, v := range values {
+		u = u.Query(k, v...)
+	}
+	return u
+}
+
+// Fragment returns a copy of the URL with the supplied fragment, which is the part after #
+func (u URL) Fragment(fragment string) URL {
+	u.fragment = fragment
+	return u
+}
+
+// String returns the encoded URL. Query parameters are sorted by key
+func (u URL) String() string {
+	sb := strings.Builder{}
+	sb.WriteString(u.base)
+	sb.WriteString(u.path)
+	if len(u.query) > 0 {
+		sb.WriteByte('?')
+		sb.WriteString(u.query.Encode())
+	}
+	if u.fragment != "" {
+		sb.WriteByte('#')
+		sb.WriteString(url.PathEscape(u.fragment))
+	}
+	return sb.String()
+}
+
+// Href emits the URL as a href attribute
+func (u URL) Href() h.Node {
+	return a.Href(u.String())
+}
+
+// Src emits the URL as a src attribute
+func (u URL) Src() h.Node {
+	return a.Src(u.String())
+}
+
+// Action emits the URL as an action attribute
+func (u URL) Action() h.Node {
+	return a.Action(u.String())
+}